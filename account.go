@@ -1,8 +1,7 @@
 package revolut
 
 import (
-	"encoding/json"
-	"errors"
+	"context"
 )
 
 // Account holds one business account, or the response from adding a counterparty.
@@ -12,19 +11,19 @@ type Account struct {
 	// Name is the display name. Not used in counterparty responses.
 	Name string `json:"name,omitempty"`
 	// Balance is not used in counterparty responses.
-	Balance float64 `json:"balance,omitempty"`
+	Balance Amount `json:"balance"`
 	// Currency is always available.
 	Currency string `json:"currency"`
 	// State is not used in counterparty responses.
-	State string `json:"state,omitempty"`
+	State AccountState `json:"state,omitempty"`
 	// Public is not used in counterparty responses.
 	Public bool `json:"public,omitempty"`
 	// Created is an ISO date/time. Not used in counterparty responses.
-	Created string `json:"created_at,omitempty"`
+	Created Time `json:"created_at,omitempty"`
 	// Updated is an ISO date/time. Mot used in counterparty responses.
-	Updated string `json:"updated_at,omitempty"`
+	Updated Time `json:"updated_at,omitempty"`
 	// Type is only used in counterparty responses.
-	Type string `json:"type,omitempty"`
+	Type CounterpartyType `json:"type,omitempty"`
 }
 
 // BankDetails can be retrieved for an account ID.
@@ -67,19 +66,13 @@ type EstimatedTime struct {
 
 // GetAccounts lists the accounts for a given API key.
 func (c *Client) GetAccounts() ([]Account, error) {
-	contents, code, err := c.GetJSON(epAccounts)
-	c.ErrorCode = code
-	if err != nil {
-		return nil, err
-	}
-
-	if code != 200 {
-		err = errors.New(codeToError(code))
-		return nil, err
-	}
+	return c.GetAccountsContext(context.Background())
+}
 
+// GetAccountsContext is GetAccounts with a context for deadlines and cancellation.
+func (c *Client) GetAccountsContext(ctx context.Context) ([]Account, error) {
 	var data []Account
-	err = json.Unmarshal(contents, &data)
+	err := c.do(ctx, "GET", epAccounts, nil, &data)
 	if err != nil {
 		return nil, err
 	}
@@ -89,26 +82,32 @@ func (c *Client) GetAccounts() ([]Account, error) {
 
 // GetAccount retrieves the basic information for a given account ID.
 func (c *Client) GetAccount(id string) (*Account, error) {
+	return c.GetAccountContext(context.Background(), id)
+}
+
+// GetAccountContext is GetAccount with a context for deadlines and cancellation.
+func (c *Client) GetAccountContext(ctx context.Context, id string) (*Account, error) {
 	var acc Account
-	contents, code, err := c.GetJSON(epAccounts + "/" + id)
-	c.ErrorCode = code
+	err := c.do(ctx, "GET", epAccounts+"/"+id, nil, &acc)
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(contents, &acc)
-	return &acc, err
+	return &acc, nil
 }
 
 // GetAccountDetails retrieves BankDetails for one specified account.
 func (c *Client) GetAccountDetails(id string) ([]BankDetails, error) {
+	return c.GetAccountDetailsContext(context.Background(), id)
+}
+
+// GetAccountDetailsContext is GetAccountDetails with a context for deadlines and cancellation.
+func (c *Client) GetAccountDetailsContext(ctx context.Context, id string) ([]BankDetails, error) {
 	var det []BankDetails
-	contents, code, err := c.GetJSON(epAccounts + "/" + id + "/" + epAccountDetails)
-	c.ErrorCode = code
+	err := c.do(ctx, "GET", epAccounts+"/"+id+"/"+epAccountDetails, nil, &det)
 	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(contents, &det)
-	return det, err
+	return det, nil
 }
@@ -0,0 +1,177 @@
+package revolut
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ibanLengths is the full IBAN length for each country in the IBAN registry.
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22, "BH": 22, "BI": 27,
+	"BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24, "DE": 22, "DJ": 27, "DK": 18, "DO": 28,
+	"EE": 20, "EG": 29, "ES": 24, "FI": 18, "FO": 18, "FR": 27, "GB": 22, "GE": 22, "GI": 23, "GL": 18,
+	"GR": 27, "GT": 28, "HR": 21, "HU": 28, "IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30,
+	"KW": 30, "KZ": 20, "LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "LY": 25, "MC": 27,
+	"MD": 24, "ME": 22, "MK": 19, "MR": 27, "MT": 31, "MU": 30, "NL": 18, "NO": 15, "PK": 24, "PL": 28,
+	"PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "RU": 33, "SA": 24, "SC": 31, "SD": 18, "SE": 24,
+	"SI": 19, "SK": 24, "SM": 27, "ST": 25, "SV": 28, "TL": 23, "TN": 24, "TR": 26, "UA": 29, "VA": 22,
+	"VG": 24, "XK": 20,
+}
+
+// IBANLength returns the length of IBANs in a country, or 0 if the country doesn't use them.
+func IBANLength(country string) int {
+	return ibanLengths[country]
+}
+
+// compact removes spaces and hyphens, as used when printing bank codes for people.
+func compact(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// isDigits reports whether s is all ASCII digits.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isUpper reports whether s is all ASCII capital letters.
+func isUpper(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'A' || s[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// isAlnum reports whether s is all ASCII digits and capital letters.
+func isAlnum(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateIBAN checks the country, length and mod-97 check digits of an IBAN.
+// Spaces and lower case letters are allowed, as in the printed form.
+func ValidateIBAN(s string) error {
+	iban := strings.ToUpper(compact(s))
+	if len(iban) < 5 || !isUpper(iban[:2]) || !isDigits(iban[2:4]) || !isAlnum(iban[4:]) {
+		return fmt.Errorf("%w: %q", ErrIBANFormat, s)
+	}
+
+	want := ibanLengths[iban[:2]]
+	if want == 0 {
+		return fmt.Errorf("%w: %s doesn't use IBANs", ErrIBANFormat, iban[:2])
+	}
+
+	if len(iban) != want {
+		return fmt.Errorf("%w: %s IBANs have %d characters, not %d", ErrIBANLength, iban[:2], want, len(iban))
+	}
+
+	// Move the country and check digits to the end, turn letters into 10-35,
+	// and take the remainder one digit at a time.
+	rem := 0
+	for _, c := range iban[4:] + iban[:4] {
+		if c >= 'A' {
+			rem = (rem*100 + int(c-'A') + 10) % 97
+		} else {
+			rem = (rem*10 + int(c-'0')) % 97
+		}
+	}
+
+	if rem != 1 {
+		return fmt.Errorf("%w: %q", ErrIBANChecksum, s)
+	}
+
+	return nil
+}
+
+// ValidateBIC checks the structure of a BIC (SWIFT code): a four-letter institution code,
+// a valid country code, a two-character location code and an optional three-character branch code.
+func ValidateBIC(s string) error {
+	bic := strings.ToUpper(strings.TrimSpace(s))
+	if len(bic) != 8 && len(bic) != 11 {
+		return fmt.Errorf("%w: %q must have 8 or 11 characters", ErrBICFormat, s)
+	}
+
+	if !isUpper(bic[:4]) || !isAlnum(bic[6:]) {
+		return fmt.Errorf("%w: %q", ErrBICFormat, s)
+	}
+
+	if !countries[bic[4:6]] {
+		return fmt.Errorf("%w: %q has an unknown country %s", ErrBICFormat, s, bic[4:6])
+	}
+
+	return nil
+}
+
+// ValidateRoutingNumber checks the length, Federal Reserve prefix and checksum of a US ABA routing number.
+func ValidateRoutingNumber(s string) error {
+	rn := compact(s)
+	if len(rn) != 9 || !isDigits(rn) {
+		return fmt.Errorf("%w: %q must have 9 digits", ErrRoutingNumber, s)
+	}
+
+	prefix := int(rn[0]-'0')*10 + int(rn[1]-'0')
+	switch {
+	case prefix <= 12, prefix >= 21 && prefix <= 32, prefix >= 61 && prefix <= 72, prefix == 80:
+	default:
+		return fmt.Errorf("%w: %q has an unused prefix", ErrRoutingNumber, s)
+	}
+
+	sum := 0
+	for i := 0; i < 9; i += 3 {
+		sum += 3*int(rn[i]-'0') + 7*int(rn[i+1]-'0') + int(rn[i+2]-'0')
+	}
+
+	if sum%10 != 0 {
+		return fmt.Errorf("%w: %q has a bad checksum", ErrRoutingNumber, s)
+	}
+
+	return nil
+}
+
+// ValidateSortCode checks that a UK sort code has 6 digits. Spaces and hyphens are allowed.
+func ValidateSortCode(s string) error {
+	sc := compact(s)
+	if len(sc) != 6 || !isDigits(sc) {
+		return fmt.Errorf("%w: %q must have 6 digits", ErrSortCode, s)
+	}
+
+	return nil
+}
+
+// ValidateUKAccount checks the format of a UK sort code and 8-digit account number,
+// then runs the modulus checks in UKModulusTable. The table is empty unless it's loaded,
+// so by default only the format is checked. Sort codes whose rules have an exception code
+// aren't checked either, so passing doesn't prove an account number is valid.
+func ValidateUKAccount(sortCode, accountNo string) error {
+	err := ValidateSortCode(sortCode)
+	if err != nil {
+		return err
+	}
+
+	acc := compact(accountNo)
+	if len(acc) != 8 || !isDigits(acc) {
+		return fmt.Errorf("%w: %q must have 8 digits", ErrAccountNumber, accountNo)
+	}
+
+	if UKModulusTable == nil {
+		return nil
+	}
+
+	return UKModulusTable.Check(compact(sortCode), acc)
+}
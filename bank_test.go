@@ -0,0 +1,215 @@
+package revolut
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateIBAN(t *testing.T) {
+	tests := []struct {
+		in   string
+		want error
+	}{
+		{"GB82WEST12345698765432", nil},
+		{"GB82 WEST 1234 5698 7654 32", nil},
+		{"gb82west12345698765432", nil},
+		{"DE89370400440532013000", nil},
+		{"NO9386011117947", nil},
+		{"GB83WEST12345698765432", ErrIBANChecksum},
+		{"GB82WEST12345698765423", ErrIBANChecksum},
+		{"GB82WEST1234569876543", ErrIBANLength},
+		{"US82WEST12345698765432", ErrIBANFormat},
+		{"GBX2WEST12345698765432", ErrIBANFormat},
+		{"GB82WEST1234569876543!", ErrIBANFormat},
+		{"GB8", ErrIBANFormat},
+	}
+
+	for _, tt := range tests {
+		err := ValidateIBAN(tt.in)
+		if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+			t.Errorf("ValidateIBAN(%q) = %v, want %v", tt.in, err, tt.want)
+		}
+	}
+}
+
+func TestValidateBIC(t *testing.T) {
+	tests := []struct {
+		in string
+		ok bool
+	}{
+		{"DEUTDEFF", true},
+		{"DEUTDEFF500", true},
+		{"nwbkgb2l", true},
+		{"NWBKGB2LXXX", true},
+		{"DEUTDEF", false},
+		{"DEUTDEFF5", false},
+		{"DEU1DEFF", false},
+		{"DEUTXXFF", false},
+		{"DEUTDEF-", false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateBIC(tt.in)
+		if (err == nil) != tt.ok || (err != nil && !errors.Is(err, ErrBICFormat)) {
+			t.Errorf("ValidateBIC(%q) = %v", tt.in, err)
+		}
+	}
+}
+
+func TestValidateRoutingNumber(t *testing.T) {
+	tests := []struct {
+		in string
+		ok bool
+	}{
+		{"021000021", true},
+		{"011000015", true},
+		{"121000358", true},
+		{"021000022", false},
+		{"02100002", false},
+		{"0210000210", false},
+		{"02100002A", false},
+		{"991000021", false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateRoutingNumber(tt.in)
+		if (err == nil) != tt.ok || (err != nil && !errors.Is(err, ErrRoutingNumber)) {
+			t.Errorf("ValidateRoutingNumber(%q) = %v", tt.in, err)
+		}
+	}
+}
+
+func TestValidateSortCode(t *testing.T) {
+	tests := []struct {
+		in string
+		ok bool
+	}{
+		{"089999", true},
+		{"08-99-99", true},
+		{"08 99 99", true},
+		{"08999", false},
+		{"0899999", false},
+		{"08-99-9A", false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateSortCode(tt.in)
+		if (err == nil) != tt.ok || (err != nil && !errors.Is(err, ErrSortCode)) {
+			t.Errorf("ValidateSortCode(%q) = %v", tt.in, err)
+		}
+	}
+}
+
+// testModulusTable has the rules for the worked examples in the Vocalink modulus checking specification.
+const testModulusTable = `
+089999 089999 MOD10  0 0 0 0 0 0 7 1 3 7 1 3 7 1
+107999 107999 MOD11  0 0 0 0 0 0 8 7 6 5 4 3 2 1
+202959 202959 DBLAL  2 1 2 1 2 1 2 1 2 1 2 1 2 1
+300000 300099 MOD11  0 0 0 0 0 0 8 7 6 5 4 3 2 1 5
+`
+
+func TestModulusCheck(t *testing.T) {
+	table, err := ParseModulusTable(strings.NewReader(testModulusTable))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(table) != 4 || table[3].Exception != 5 || table[2].Method != ModulusDBLAL {
+		t.Fatalf("parsed %+v", table)
+	}
+
+	tests := []struct {
+		name      string
+		sortCode  string
+		accountNo string
+		want      error
+	}{
+		{"MOD10", "089999", "66374958", nil},
+		{"MOD10 wrong", "089999", "66374959", ErrModulusCheck},
+		{"MOD11", "107999", "88837491", nil},
+		{"MOD11 wrong", "107999", "88837492", ErrModulusCheck},
+		{"DBLAL", "202959", "63748472", nil},
+		{"DBLAL wrong", "202959", "63748473", ErrModulusCheck},
+		{"exception skipped", "300050", "12345678", nil},
+		{"no rule", "400000", "12345678", nil},
+		{"short sort code", "08999", "66374958", ErrSortCode},
+		{"short account", "089999", "6637495", ErrAccountNumber},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := table.Check(tt.sortCode, tt.accountNo)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseModulusTableErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"too few fields", "089999 089999 MOD10 0 0 0 0 0 0 7 1 3 7 1 3 7"},
+		{"too many fields", "089999 089999 MOD10 0 0 0 0 0 0 7 1 3 7 1 3 7 1 5 6"},
+		{"unknown method", "089999 089999 MOD12 0 0 0 0 0 0 7 1 3 7 1 3 7 1"},
+		{"bad sort code", "08999X 089999 MOD10 0 0 0 0 0 0 7 1 3 7 1 3 7 1"},
+		{"bad weight", "089999 089999 MOD10 0 0 0 0 0 0 7 1 3 7 1 3 7 X"},
+		{"bad exception", "089999 089999 MOD10 0 0 0 0 0 0 7 1 3 7 1 3 7 1 X"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseModulusTable(strings.NewReader(tt.in))
+		if err == nil || !strings.Contains(err.Error(), "line 1") {
+			t.Errorf("%s: got %v, want an error for line 1", tt.name, err)
+		}
+	}
+}
+
+func TestLoadUKModulusTable(t *testing.T) {
+	dir, err := ioutil.TempDir("", "modulus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	old := UKModulusTable
+	defer func() { UKModulusTable = old }()
+	UKModulusTable = nil
+
+	err = ValidateUKAccount("08-99-99", "66374959")
+	if err != nil {
+		t.Errorf("without a table: %v", err)
+	}
+
+	path := filepath.Join(dir, "valacdos.txt")
+	err = LoadUKModulusTable(path)
+	if err == nil || UKModulusTable != nil {
+		t.Errorf("missing file: got %v and %d rules", err, len(UKModulusTable))
+	}
+
+	err = ioutil.WriteFile(path, []byte(testModulusTable), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = LoadUKModulusTable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ValidateUKAccount("08-99-99", "66374959")
+	if !errors.Is(err, ErrModulusCheck) {
+		t.Errorf("with a table: got %v, want ErrModulusCheck", err)
+	}
+
+	err = ValidateUKAccount("08-99-99", "6637 4958")
+	if err != nil {
+		t.Errorf("with a table: %v", err)
+	}
+}
@@ -3,11 +3,13 @@ package revolut
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
-	"strings"
 	"time"
 )
 
@@ -15,12 +17,23 @@ import (
 type Client struct {
 	http.Client
 	baseURL string
-	//ErrorCode is the last HTTP error code.
-	ErrorCode int
-	// Agent should be customised per app.
+	// Agent should be customised per app. It is sent as the User-Agent header.
 	Agent string
+	// Header holds default headers added to every request.
+	Header http.Header
+	// Retry controls how failed requests are retried. Set MaxAttempts to 1 to disable.
+	Retry RetryPolicy
+	// MaxResponseSize is the most bytes read from a response body. DefaultMaxResponseSize is used if it's 0.
+	MaxResponseSize int64
+	// Limiter optionally throttles every request, including retries. Share one between clients using the same key.
+	Limiter *RateLimiter
+	// RequestIDs makes request IDs for payments, transfers and exchanges sent without one.
+	// If it's nil they're sent without, and aren't retried.
+	RequestIDs RequestIDGenerator
 	// bearer is the authentication header string, generated from the API key.
 	bearer string
+	// middleware wraps the transport, outermost first.
+	middleware []Middleware
 }
 
 // ErrorResponse from JSON endpoints.
@@ -31,6 +44,9 @@ type ErrorResponse struct {
 	Code int `json:"code"`
 }
 
+// DefaultMaxResponseSize limits response bodies to 10 MiB unless the client sets its own limit.
+const DefaultMaxResponseSize = 10 << 20
+
 const (
 	defaultAgent  = "Revolut unofficial Go SDK"
 	urlSandbox    = "https://sandbox-b2b.revolut.com/api/1.0/"
@@ -49,7 +65,9 @@ const (
 	epPay            = "pay"
 	epTransaction    = "transaction"
 	epTransactions   = "transactions"
-	epWebhook        = "webhook"
+	epWebhooks       = "webhooks"
+	epRate           = "rate"
+	epExchange       = "exchange"
 
 	//
 	// Webhook events
@@ -62,15 +80,28 @@ const (
 )
 
 // NewClient creates a new Revolut client with some reasonable HTTP request defaults.
-func NewClient(key string) (*Client, error) {
+// Options are applied after the API key, so WithBaseURL overrides the URL chosen from it.
+func NewClient(key string, opts ...Option) (*Client, error) {
 	c := Client{}
 	c.Timeout = time.Second * 5
 	c.Agent = defaultAgent
+	c.Retry = DefaultRetryPolicy
 	c.Transport = &http.Transport{
 		MaxIdleConns:        50,
 		MaxIdleConnsPerHost: 50,
 	}
-	return &c, c.SetAPI(key)
+	err := c.SetAPI(key)
+	if err != nil {
+		return &c, err
+	}
+
+	for _, o := range opts {
+		o(&c)
+	}
+	if len(c.middleware) > 0 {
+		c.Transport = chain(c.Transport, c.middleware)
+	}
+	return &c, nil
 }
 
 // SetAPI sets the API key and type to use (sandbox or production).
@@ -91,93 +122,150 @@ func (c *Client) SetAPI(key string) error {
 
 // GetJSON builds the full endpoint path and gets the raw JSON.
 func (c *Client) GetJSON(path string) ([]byte, int, error) {
-	var url strings.Builder
-	url.WriteString(c.baseURL)
-	url.WriteString(path)
-	req, err := http.NewRequest("GET", url.String(), nil)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	c.setHeader(req)
-	response, err := c.Do(req)
-	defer response.Body.Close()
-	if err != nil {
-		return nil, response.StatusCode, err
-	}
-
-	contents, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return nil, response.StatusCode, err
-	}
+	return c.GetJSONContext(context.Background(), path)
+}
 
-	return contents, response.StatusCode, nil
+// GetJSONContext is GetJSON with a context for deadlines and cancellation.
+func (c *Client) GetJSONContext(ctx context.Context, path string) ([]byte, int, error) {
+	return c.request(ctx, "GET", path, nil, true)
 }
 
 // PostJSON builds the full endpoint path and posts the provided data, returning the JSON response.
 func (c *Client) PostJSON(path string, data interface{}) ([]byte, int, error) {
+	return c.PostJSONContext(context.Background(), path, data)
+}
+
+// PostJSONContext is PostJSON with a context for deadlines and cancellation.
+// Posts are never retried, since they may not be safe to repeat.
+func (c *Client) PostJSONContext(ctx context.Context, path string, data interface{}) ([]byte, int, error) {
 	msg, err := json.Marshal(data)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	url := strings.Join([]string{c.baseURL, path}, "")
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(msg))
-	if err != nil {
-		return nil, 0, err
+	return c.request(ctx, "POST", path, msg, false)
+}
+
+// Delete sends a delete command to an endpoint. The URL is the data and the HTTP response code is the only result.
+func (c *Client) Delete(path string) ([]byte, int, error) {
+	return c.DeleteContext(context.Background(), path)
+}
+
+// DeleteContext is Delete with a context for deadlines and cancellation.
+func (c *Client) DeleteContext(ctx context.Context, path string) ([]byte, int, error) {
+	return c.request(ctx, "DELETE", path, nil, true)
+}
+
+// idempotent is implemented by request bodies which carry a client-supplied request ID.
+// The API won't act twice on the same ID, so such posts are safe to retry.
+type idempotent interface {
+	requestID() string
+}
+
+// do is what the API calls are built on. It marshals body if there is one, sends the request
+// and decodes a successful response into out. Pass nil for out when no response body is expected.
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var msg []byte
+	var id string
+	if body != nil {
+		var err error
+		msg, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		if i, ok := body.(idempotent); ok {
+			id = i.requestID()
+		}
 	}
 
-	c.setHeader(req)
-	response, err := c.Do(req)
-	defer response.Body.Close()
+	retry := method != "POST" || id != ""
+	contents, _, err := c.request(ctx, method, path, msg, retry)
 	if err != nil {
-		_, _ = ioutil.ReadAll(response.Body)
-		return nil, response.StatusCode, err
+		return withRequestID(err, id)
+	}
+
+	if out == nil {
+		return nil
 	}
 
-	contents, err := ioutil.ReadAll(response.Body)
+	if len(bytes.TrimSpace(contents)) == 0 {
+		return fmt.Errorf("%s %s: %w", method, path, ErrEmptyResponse)
+	}
+
+	err = json.Unmarshal(contents, out)
 	if err != nil {
-		return nil, response.StatusCode, err
+		return fmt.Errorf("%s %s: decoding response: %w", method, path, err)
 	}
 
-	return contents, response.StatusCode, nil
+	return nil
 }
 
-// Delete sends a delete command to an endpoint. The URL is the data and the HTTP response code is the only result.
-func (c *Client) Delete(path string) ([]byte, int, error) {
-	url := strings.Join([]string{c.baseURL, path}, "")
-	req, err := http.NewRequest("DELETE", url, nil)
+// request is the core of every API call. It builds the full endpoint URL,
+// sends the request bound to ctx and returns the raw response body and status code.
+// Any status outside the 2xx range is returned as an *APIError.
+// Transient failures are retried according to the client's RetryPolicy if retry is set.
+func (c *Client) request(ctx context.Context, method, path string, body []byte, retry bool) ([]byte, int, error) {
+	attempts := 1
+	if retry && c.Retry.MaxAttempts > 1 {
+		attempts = c.Retry.MaxAttempts
+	}
+
+	cid := correlationID(ctx)
+	for i := 1; ; i++ {
+		contents, code, wait, err := c.send(ctx, method, path, body, cid)
+		if i >= attempts || !retryable(ctx, err) {
+			return contents, code, err
+		}
+
+		t := time.NewTimer(c.Retry.delay(i, wait))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return contents, code, err
+		case <-t.C:
+		}
+	}
+}
+
+// send performs a single round trip, also returning the server's Retry-After delay if one was given.
+func (c *Client) send(ctx context.Context, method, path string, body []byte, cid string) ([]byte, int, time.Duration, error) {
+	if c.Limiter != nil {
+		err := c.Limiter.Wait(ctx)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+	}
+
+	req, err := c.newRequest(ctx, method, path, body, cid)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, 0, err
 	}
 
-	c.setHeader(req)
 	response, err := c.Do(req)
-	defer response.Body.Close()
 	if err != nil {
-		return nil, response.StatusCode, err
+		return nil, 0, 0, err
 	}
 
-	contents, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return nil, response.StatusCode, err
+	defer response.Body.Close()
+	wait := retryAfter(response.Header.Get("Retry-After"))
+	max := c.MaxResponseSize
+	if max <= 0 {
+		max = DefaultMaxResponseSize
 	}
 
-	return contents, response.StatusCode, nil
-}
+	contents, err := ioutil.ReadAll(io.LimitReader(response.Body, max+1))
+	if err != nil {
+		return nil, response.StatusCode, wait, err
+	}
 
-// setHeader helper function.
-func (c *Client) setHeader(req *http.Request) {
-	req.Header.Set("Authorization", c.bearer)
-}
+	if int64(len(contents)) > max {
+		return nil, response.StatusCode, wait, fmt.Errorf("%s %s: %w", method, path, ErrResponseTooLarge)
+	}
 
-// jsonError extracts the message from a JSON error response
-func jsonError(data []byte) error {
-	var resp ErrorResponse
-	err := json.Unmarshal(data, &resp)
-	if err != nil {
-		return err
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return contents, response.StatusCode, wait, newAPIError(method, path, cid, response.StatusCode, contents)
 	}
 
-	return errors.New(resp.Message)
+	return contents, response.StatusCode, wait, nil
 }
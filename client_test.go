@@ -0,0 +1,216 @@
+package revolut
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDoResponses(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		closed bool
+		check  func(err error) bool
+	}{
+		{
+			name:   "network failure",
+			closed: true,
+			check:  func(err error) bool { return err != nil },
+		},
+		{
+			name:  "empty body",
+			body:  " \n",
+			check: func(err error) bool { return errors.Is(err, ErrEmptyResponse) },
+		},
+		{
+			name: "non-JSON body",
+			body: "<html>gateway error</html>",
+			check: func(err error) bool {
+				var se *json.SyntaxError
+				return errors.As(err, &se) && strings.Contains(err.Error(), "decoding response")
+			},
+		},
+		{
+			name:  "oversized body",
+			body:  `{"id":"` + strings.Repeat("x", 100) + `"}`,
+			check: func(err error) bool { return errors.Is(err, ErrResponseTooLarge) },
+		},
+		{
+			name:  "valid body",
+			body:  `{"id":"abc"}`,
+			check: func(err error) bool { return err == nil },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			if tt.closed {
+				srv.Close()
+			} else {
+				defer srv.Close()
+			}
+
+			c := newTestClient(t, srv.URL)
+			c.MaxResponseSize = 64
+			var out struct {
+				ID string `json:"id"`
+			}
+			err := c.do(context.Background(), "GET", "things", nil, &out)
+			if !tt.check(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Second * 5}
+	want := []time.Duration{time.Second, time.Second * 2, time.Second * 4, time.Second * 5, time.Second * 5}
+	for i, w := range want {
+		d := p.delay(i+1, 0)
+		if d != w {
+			t.Errorf("attempt %d: delay %v, want %v", i+1, d, w)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := p.delay(2, 0)
+		if d < time.Second || d > time.Second*2 {
+			t.Fatalf("jittered delay %v outside 1s-2s", d)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		max        time.Duration
+		retryAfter time.Duration
+		want       time.Duration
+	}{
+		{"over MaxDelay", 0, time.Second * 30, time.Second * 30},
+		{"default cap", 0, time.Hour * 24, DefaultMaxRetryAfter},
+		{"policy cap", time.Second * 20, time.Second * 30, time.Second * 20},
+		{"under cap", time.Second * 20, time.Second * 3, time.Second * 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Second * 5, MaxRetryAfter: tt.max, Jitter: 1}
+			d := p.delay(1, tt.retryAfter)
+			if d != tt.want {
+				t.Errorf("delay %v, want %v", d, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	future := time.Now().Add(time.Minute * 2).UTC().Format(http.TimeFormat)
+	past := time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)
+	tests := []struct {
+		header   string
+		min, max time.Duration
+	}{
+		{"", 0, 0},
+		{"7", time.Second * 7, time.Second * 7},
+		{"-3", 0, 0},
+		{"soon", 0, 0},
+		{past, 0, 0},
+		{future, time.Minute, time.Minute * 2},
+	}
+
+	for _, tt := range tests {
+		d := retryAfter(tt.header)
+		if d < tt.min || d > tt.max {
+			t.Errorf("retryAfter(%q) = %v, want %v-%v", tt.header, d, tt.min, tt.max)
+		}
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	ctx := context.Background()
+	done, cancel := context.WithCancel(ctx)
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{"rate limited", ctx, &APIError{Status: 429}, true},
+		{"unavailable", ctx, &APIError{Status: 503}, true},
+		{"bad request", ctx, &APIError{Status: 400}, false},
+		{"too large", ctx, fmt.Errorf("GET things: %w", ErrResponseTooLarge), false},
+		{"cut short", ctx, io.ErrUnexpectedEOF, true},
+		{"connection refused", ctx, &url.Error{Op: "Get", URL: "x", Err: &net.OpError{Op: "dial", Err: errors.New("refused")}}, true},
+		{"connection closed", ctx, &url.Error{Op: "Get", URL: "x", Err: io.EOF}, true},
+		{"bad URL", ctx, &url.Error{Op: "parse", URL: "%zz", Err: url.EscapeError("%zz")}, false},
+		{"cancelled", done, &APIError{Status: 503}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryable(tt.ctx, tt.err); got != tt.want {
+				t.Errorf("retryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   int
+	}{
+		{name: "server error", status: 503, want: 3},
+		{name: "client error", status: 400, want: 1},
+		{name: "oversized body", status: 200, body: strings.Repeat("x", 100), want: 1},
+		{name: "bad URL", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			c := newTestClient(t, srv.URL, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+			c.MaxResponseSize = 64
+			path := "things"
+			if tt.want == 0 {
+				path = "%zz"
+			}
+
+			_, _, err := c.GetJSONContext(context.Background(), path)
+			if err == nil {
+				t.Fatal("no error")
+			}
+
+			n := int(atomic.LoadInt32(&calls))
+			if n != tt.want {
+				t.Errorf("got %d requests, want %d", n, tt.want)
+			}
+		})
+	}
+}
@@ -81,7 +81,8 @@ func showAccount(acc *revolut.Account, short bool) {
 	if short {
 		acc.ID = shortUUID(acc.ID)
 	}
-	slog.Msg("%s (%s): %s - %f %s", acc.ID, acc.State, acc.Name, acc.Balance, acc.Currency)
+	bal := revolut.Money{Amount: acc.Balance, Currency: acc.Currency}
+	slog.Msg("%s (%s): %s - %s", acc.ID, acc.State, acc.Name, bal)
 }
 
 func showDetails(det []revolut.BankDetails) {
@@ -0,0 +1,625 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Urethramancer/revolut"
+	"github.com/Urethramancer/slog"
+)
+
+// PayBulkCmd pays many counterparties from a CSV file.
+type PayBulkCmd struct {
+	DryRunOption
+	Account  string `short:"a" long:"account" description:"UUID of the account to pay from. Defaults to your only active account in each payment's currency." value-name:"ACCOUNT"`
+	State    string `long:"state" description:"File recording each payment sent, so a re-run skips them. Defaults to the CSV file name with .state added." value-name:"FILE"`
+	NewBatch bool   `long:"new-batch" description:"Pay every line again as a new batch, instead of resuming the batch in the state file."`
+	Yes      bool   `short:"y" long:"yes" description:"Don't ask for confirmation after showing the summary."`
+	Args     struct {
+		Filename string `required:"true" positional-arg-name:"FILENAME" description:"CSV file with counterparty UUID or exact name, amount, currency, reference and optional schedule date on each line."`
+	} `positional-args:"true"`
+}
+
+// bulkPayment is one line of the CSV file, resolved into a request.
+type bulkPayment struct {
+	line int
+	// record is the line as read from the CSV file.
+	record []string
+	// copy is how many identical records came before it in the file.
+	copy  int
+	name  string
+	money revolut.Money
+	req   revolut.PaymentRequest
+}
+
+// bulkResult is one line of the state file.
+type bulkResult struct {
+	// Batch starts a new batch on a line of its own. Its random ID is mixed into the request IDs,
+	// so paying the same file again is never mistaken for a retry of the last batch.
+	Batch string `json:"batch,omitempty"`
+	// RequestID of the payment.
+	RequestID string `json:"request_id,omitempty"`
+	// Line in the CSV file.
+	Line int `json:"line,omitempty"`
+	// Record is the line as read from the CSV file. A resumed batch gives the same record the same request ID.
+	Record []string `json:"record,omitempty"`
+	// Copy is how many identical records came before it in the CSV file.
+	Copy int `json:"copy,omitempty"`
+	// Transaction is the ID of the created payment. It's empty until the API has answered.
+	Transaction string `json:"transaction_id,omitempty"`
+	// State of the payment when it was created.
+	State revolut.TransactionState `json:"state,omitempty"`
+	// Error from the API, if the payment wasn't created.
+	Error string `json:"error,omitempty"`
+	// Time of the entry.
+	Time time.Time `json:"time"`
+}
+
+// Execute the bulk payment.
+func (cmd *PayBulkCmd) Execute(args []string) error {
+	lines, err := readBulkFile(cmd.Args.Filename)
+	if err != nil {
+		return err
+	}
+
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	snap, err := c.Snapshot()
+	if err != nil {
+		return err
+	}
+
+	list, err := cmd.resolve(lines, snap)
+	if err != nil {
+		return err
+	}
+
+	if cmd.State == "" {
+		cmd.State = cmd.Args.Filename + ".state"
+	}
+
+	state, err := loadBulkState(cmd.State, cmd.NewBatch)
+	if err != nil {
+		return err
+	}
+
+	err = assignBulkIDs(list, state)
+	if err != nil {
+		return err
+	}
+
+	done := state.Done
+	ctx := context.Background()
+	var pending []bulkPayment
+	totals := make(map[string]revolut.Amount)
+	failed := false
+	for _, p := range list {
+		if done[p.req.RequestID] != "" {
+			slog.Msg("%4d  %-30s %16s  paid as %s", p.line, p.name, p.money, done[p.req.RequestID])
+			continue
+		}
+
+		slog.Msg("%4d  %-30s %16s  %s", p.line, p.name, p.money, p.req.Reference)
+		err = p.req.Validate(ctx, snap)
+		if err != nil {
+			failed = true
+			var ve revolut.ValidationError
+			if !errors.As(err, &ve) {
+				return err
+			}
+
+			for _, fe := range ve {
+				slog.Error("      %s", fe.Error())
+			}
+		}
+
+		pending = append(pending, p)
+		totals[p.money.Currency], err = totals[p.money.Currency].Add(p.money.Amount)
+		if err != nil {
+			return fmt.Errorf("line %d: total of %s payments: %w", p.line, p.money.Currency, err)
+		}
+	}
+
+	if len(pending) == 0 {
+		slog.Msg("All %d payments in batch %s have been sent. Use --new-batch to pay them again.", len(list), state.Batch)
+		return nil
+	}
+
+	slog.Msg("")
+	slog.Msg("%d of %d payments to send:", len(pending), len(list))
+	var currencies []string
+	for cur := range totals {
+		currencies = append(currencies, cur)
+	}
+	sort.Strings(currencies)
+	for _, cur := range currencies {
+		slog.Msg("%16s", revolut.Money{Amount: totals[cur], Currency: cur})
+	}
+
+	if !checkBulkBalances(pending, snap) || failed {
+		return errors.New("no payments sent")
+	}
+
+	if cmd.DryRun {
+		slog.Msg("Nothing was sent.")
+		return nil
+	}
+
+	if !cmd.Yes && !confirm(fmt.Sprintf("Send %d payments?", len(pending))) {
+		slog.Msg("Cancelled.")
+		return nil
+	}
+
+	return cmd.send(ctx, c, state, pending)
+}
+
+// send pays each payment, recording it in the state file before and after.
+// A payment recorded as sent without an answer is sent again with the same request ID,
+// which the API recognises, so it isn't paid twice.
+func (cmd *PayBulkCmd) send(ctx context.Context, c *revolut.Client, state *bulkState, list []bulkPayment) error {
+	f, err := os.OpenFile(cmd.State, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+	err = state.start(f)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range list {
+		res := bulkResult{RequestID: p.req.RequestID, Line: p.line, Record: p.record, Copy: p.copy, Time: time.Now()}
+		err := writeBulkResult(f, res)
+		if err != nil {
+			return err
+		}
+
+		r := p.req
+		resp, err := c.PayContext(ctx, r.RequestID, r.AccountID, r.Receiver.CounterpartyID, r.Receiver.AccountID, r.Currency, r.Reference, r.ScheduleTime, r.Amount)
+		res.Time = time.Now()
+		if err != nil {
+			res.Error = err.Error()
+			werr := writeBulkResult(f, res)
+			if werr != nil {
+				return fmt.Errorf("line %d: %v, and recording it failed: %w", p.line, err, werr)
+			}
+
+			return fmt.Errorf("line %d: %w", p.line, err)
+		}
+
+		res.Transaction = resp.ID
+		res.State = resp.State
+		err = writeBulkResult(f, res)
+		if err != nil {
+			return err
+		}
+
+		slog.Msg("%4d  %-30s %16s  %s %s", p.line, p.name, p.money, resp.ID, resp.State)
+	}
+
+	return nil
+}
+
+// bulkLine is one record of the CSV file.
+type bulkLine struct {
+	// n is the line the record ends on, which is the line it's on unless a quoted field spans lines.
+	n      int
+	fields []string
+}
+
+// utf8BOM is the byte order mark some spreadsheets put at the start of exported files.
+const utf8BOM = "\xEF\xBB\xBF"
+
+// readBulkFile reads the CSV records of a bulk payment file, skipping a header line.
+// Blank lines and lines starting with # are ignored.
+func readBulkFile(path string) ([]bulkLine, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+	br := bufio.NewReader(f)
+	bom, _ := br.Peek(len(utf8BOM))
+	if string(bom) == utf8BOM {
+		br.Discard(len(utf8BOM))
+	}
+
+	lr := &lineReader{r: br}
+	r := csv.NewReader(lr)
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	var lines []bulkLine
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(lines) == 0 && isBulkHeader(rec) {
+			continue
+		}
+
+		if len(rec) < 4 || len(rec) > 5 {
+			return nil, fmt.Errorf("line %d: expected 4 or 5 columns, got %d", lr.n, len(rec))
+		}
+
+		lines = append(lines, bulkLine{n: lr.n, fields: rec})
+	}
+
+	return lines, nil
+}
+
+// bulkColumns names the columns of a bulk payment file, as used in its optional header line.
+var bulkColumns = []string{"counterparty", "amount", "currency", "reference", "schedule"}
+
+// isBulkHeader reports whether a record is the header line, rather than a payment to a
+// counterparty which happens to be called "counterparty".
+func isBulkHeader(rec []string) bool {
+	if len(rec) < 4 || len(rec) > len(bulkColumns) {
+		return false
+	}
+
+	for i, f := range rec {
+		if !strings.EqualFold(strings.TrimSpace(f), bulkColumns[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// lineReader gives a csv.Reader at most one line per Read and counts them, so after each record
+// n is the line it ended on. csv.Reader.FieldPos does this from Go 1.17, but 1.13 is supported.
+type lineReader struct {
+	r    *bufio.Reader
+	rest []byte
+	n    int
+	// mid is set while the last line read hasn't ended, because it's longer than the buffer.
+	mid bool
+}
+
+// Read returns the rest of the current line, or the next one.
+func (l *lineReader) Read(p []byte) (int, error) {
+	if len(l.rest) == 0 {
+		line, err := l.r.ReadSlice('\n')
+		if len(line) == 0 {
+			return 0, err
+		}
+
+		if !l.mid {
+			l.n++
+		}
+		l.mid = line[len(line)-1] != '\n'
+		l.rest = line
+	}
+
+	n := copy(p, l.rest)
+	l.rest = l.rest[n:]
+	return n, nil
+}
+
+// resolve turns CSV lines into payment requests, finding the counterparties, their accounts and
+// the accounts to pay from. Every line with a problem is shown before an error is returned.
+func (cmd *PayBulkCmd) resolve(lines []bulkLine, snap *revolut.Snapshot) ([]bulkPayment, error) {
+	var list []bulkPayment
+	failed := false
+	for _, l := range lines {
+		p, err := cmd.resolveLine(l, snap)
+		if err != nil {
+			slog.Error("Line %d: %s", l.n, err.Error())
+			failed = true
+			continue
+		}
+
+		list = append(list, p)
+	}
+
+	if failed {
+		return nil, errors.New("no payments sent")
+	}
+
+	return list, nil
+}
+
+// resolveLine parses one CSV line: counterparty, amount, currency, reference and optional schedule.
+func (cmd *PayBulkCmd) resolveLine(l bulkLine, snap *revolut.Snapshot) (bulkPayment, error) {
+	p := bulkPayment{line: l.n, record: l.fields}
+	rec := l.fields
+	cp, err := findBulkCounterparty(snap, strings.TrimSpace(rec[0]))
+	if err != nil {
+		return p, err
+	}
+
+	p.name = cp.Name
+	p.money, err = revolut.ParseMoney(rec[1], strings.TrimSpace(rec[2]))
+	if err != nil {
+		return p, err
+	}
+
+	p.req.Amount = p.money.Amount
+	p.req.Currency = p.money.Currency
+	p.req.Receiver.CounterpartyID = cp.ID
+	p.req.Reference = strings.TrimSpace(rec[3])
+	if len(rec) > 4 {
+		p.req.ScheduleTime = strings.TrimSpace(rec[4])
+	}
+
+	for _, acc := range cp.Accounts {
+		if acc.Currency != p.money.Currency {
+			continue
+		}
+
+		if p.req.Receiver.AccountID != "" {
+			return p, fmt.Errorf("%s has more than one %s account", cp.Name, acc.Currency)
+		}
+
+		p.req.Receiver.AccountID = acc.ID
+	}
+
+	p.req.AccountID = cmd.Account
+	if p.req.AccountID != "" {
+		return p, nil
+	}
+
+	for _, acc := range snap.Accounts {
+		if acc.Currency != p.money.Currency || !acc.State.IsActive() {
+			continue
+		}
+
+		if p.req.AccountID != "" {
+			return p, fmt.Errorf("more than one %s account to pay from - choose one with --account", acc.Currency)
+		}
+
+		p.req.AccountID = acc.ID
+	}
+
+	if p.req.AccountID == "" {
+		return p, fmt.Errorf("no active %s account to pay from", p.money.Currency)
+	}
+
+	return p, nil
+}
+
+// findBulkCounterparty finds a current counterparty by UUID or exact name.
+func findBulkCounterparty(snap *revolut.Snapshot, s string) (*revolut.Counterparty, error) {
+	var found *revolut.Counterparty
+	for i, cp := range snap.Counterparties {
+		if cp.State == revolut.CounterpartyDeleted {
+			continue
+		}
+
+		if cp.ID == s {
+			return &snap.Counterparties[i], nil
+		}
+
+		if cp.Name == s {
+			if found != nil {
+				return nil, fmt.Errorf("more than one counterparty is called %s - use the UUID", s)
+			}
+			found = &snap.Counterparties[i]
+		}
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("no counterparty %s", s)
+	}
+
+	return found, nil
+}
+
+// bulkKey joins the fields which make a payment unique, to count identical lines.
+func bulkKey(r revolut.PaymentRequest) string {
+	m := revolut.Money{Amount: r.Amount, Currency: r.Currency}
+	minor, _ := m.Minor()
+	return strings.Join([]string{r.AccountID, r.Receiver.CounterpartyID, r.Receiver.AccountID,
+		fmt.Sprint(minor), r.Currency, r.Reference, r.ScheduleTime}, "|")
+}
+
+// recordKey identifies a CSV record and how many identical ones came before it.
+func recordKey(record []string, copy int) string {
+	return strings.Join(record, "\x1f") + "\x1f" + strconv.Itoa(copy)
+}
+
+// assignBulkIDs gives each payment the request ID recorded for its CSV record in the state file.
+// That ID is kept even if the counterparty or accounts it resolves to have changed since, so a
+// resumed batch never pays a line twice. Payments not in the state file get a new revolut.ContentHash
+// request ID, with the batch and how many identical payments came before in the namespace, skipping
+// any ID already recorded. Unscheduled payments use the day the batch started as their date.
+func assignBulkIDs(list []bulkPayment, state *bulkState) error {
+	used := make(map[string]bool)
+	for _, id := range state.IDs {
+		used[id] = true
+	}
+
+	copies := make(map[string]int)
+	for i := range list {
+		p := &list[i]
+		rk := recordKey(p.record, 0)
+		p.copy = copies[rk]
+		copies[rk]++
+		id, ok := state.IDs[recordKey(p.record, p.copy)]
+		if ok {
+			p.req.RequestID = id
+		}
+	}
+
+	seen := make(map[string]int)
+	for i := range list {
+		r := &list[i].req
+		if r.RequestID != "" {
+			continue
+		}
+
+		key := bulkKey(*r)
+		for r.RequestID == "" || used[r.RequestID] {
+			gen := revolut.ContentHash{
+				Namespace: fmt.Sprintf("%s bulk %s %d", programName, state.Batch, seen[key]),
+				Now:       func() time.Time { return state.Started },
+			}
+			seen[key]++
+			id, err := gen.RequestID(revolut.RequestDetails{
+				Account:         r.AccountID,
+				Counterparty:    r.Receiver.CounterpartyID,
+				ReceiverAccount: r.Receiver.AccountID,
+				Amount:          r.Amount,
+				Currency:        r.Currency,
+				Reference:       r.Reference,
+				Date:            revolut.ScheduleDate(r.ScheduleTime),
+			})
+			if err != nil {
+				return err
+			}
+
+			r.RequestID = id
+		}
+		used[r.RequestID] = true
+	}
+
+	return nil
+}
+
+// checkBulkBalances shows every account whose balance doesn't cover its total of unscheduled payments.
+func checkBulkBalances(list []bulkPayment, snap *revolut.Snapshot) bool {
+	ok := true
+	totals := make(map[string]revolut.Amount)
+	for _, p := range list {
+		if p.req.ScheduleTime == "" {
+			total, err := totals[p.req.AccountID].Add(p.req.Amount)
+			if err != nil {
+				slog.Error("%4d  %s", p.line, err)
+				ok = false
+			}
+			totals[p.req.AccountID] = total
+		}
+	}
+
+	for _, acc := range snap.Accounts {
+		total, found := totals[acc.ID]
+		if found && acc.Balance.Cmp(total) < 0 {
+			slog.Error("%s has %s %s, but the payments total %s %s.", acc.Name, acc.Balance, acc.Currency, total, acc.Currency)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// bulkState is the last batch in a state file.
+type bulkState struct {
+	// Batch is the random ID of the batch.
+	Batch string
+	// Started is when the batch was created.
+	Started time.Time
+	// Done holds the transaction ID of every payment in the batch the API has accepted, by request ID.
+	Done map[string]string
+	// IDs holds the request ID of every payment recorded in the batch, by recordKey.
+	IDs map[string]string
+	// new is set until the batch has been written to the file.
+	new bool
+}
+
+// loadBulkState reads the last batch from a state file. A new batch is made if the file is
+// missing or has none, or if newBatch is set.
+func loadBulkState(path string, newBatch bool) (*bulkState, error) {
+	state := newBulkState("", time.Time{})
+	f, err := os.Open(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if err == nil {
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var res bulkResult
+			err = json.Unmarshal(scanner.Bytes(), &res)
+			if err != nil {
+				// A crash can leave a partial last line.
+				continue
+			}
+
+			if res.Batch != "" {
+				state = newBulkState(res.Batch, res.Time)
+				continue
+			}
+
+			if res.Record != nil && res.RequestID != "" {
+				state.IDs[recordKey(res.Record, res.Copy)] = res.RequestID
+			}
+			if res.Transaction != "" {
+				state.Done[res.RequestID] = res.Transaction
+			}
+		}
+
+		err = scanner.Err()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if state.Batch != "" && !newBatch {
+		return state, nil
+	}
+
+	batch, err := revolut.UUIDv4{}.RequestID(revolut.RequestDetails{})
+	if err != nil {
+		return nil, err
+	}
+
+	state = newBulkState(batch, time.Now())
+	state.new = true
+	return state, nil
+}
+
+// newBulkState creates an empty batch.
+func newBulkState(batch string, started time.Time) *bulkState {
+	return &bulkState{Batch: batch, Started: started, Done: make(map[string]string), IDs: make(map[string]string)}
+}
+
+// start writes a new batch to the state file. Resumed batches are already there.
+func (s *bulkState) start(f *os.File) error {
+	if !s.new {
+		return nil
+	}
+
+	err := writeBulkResult(f, bulkResult{Batch: s.Batch, Time: s.Started})
+	if err != nil {
+		return err
+	}
+
+	s.new = false
+	return nil
+}
+
+// writeBulkResult appends a line to the state file and syncs it to disk.
+func writeBulkResult(f *os.File, res bulkResult) error {
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(append(data, '\n'))
+	if err != nil {
+		return err
+	}
+
+	return f.Sync()
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Urethramancer/revolut"
+)
+
+// writeTemp writes data to a temporary file, returning its path and a function removing it.
+func writeTemp(t *testing.T, data string) (string, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "bulk")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "bulk.csv")
+	err = ioutil.WriteFile(path, []byte(data), 0600)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func TestReadBulkFile(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		lines []int
+		refs  []string
+	}{
+		{
+			name:  "header and comments",
+			data:  "counterparty,amount,currency,reference\n# paid monthly\n\nAcme,10,GBP,Rent\nBob, 5.50, GBP, Lunch\n",
+			lines: []int{4, 5},
+			refs:  []string{"Rent", "Lunch"},
+		},
+		{
+			name:  "counterparty called counterparty",
+			data:  "Counterparty,10,GBP,Rent\nAcme,5,GBP,Lunch\n",
+			lines: []int{1, 2},
+			refs:  []string{"Rent", "Lunch"},
+		},
+		{
+			name:  "header with schedule",
+			data:  "counterparty, amount, currency, reference, schedule\nAcme,10,GBP,Rent,2030-01-01\n",
+			lines: []int{2},
+			refs:  []string{"Rent"},
+		},
+		{
+			name:  "byte order mark",
+			data:  utf8BOM + "Counterparty,Amount,Currency,Reference\r\nAcme,10,GBP,Rent\r\n",
+			lines: []int{2},
+			refs:  []string{"Rent"},
+		},
+		{
+			name:  "quoted newline",
+			data:  "Acme,10,GBP,\"Invoice 1\nand 2\"\nBob,5,GBP,Lunch",
+			lines: []int{2, 3},
+			refs:  []string{"Invoice 1\nand 2", "Lunch"},
+		},
+		{
+			name:  "long line",
+			data:  "Acme,10,GBP," + strings.Repeat("x", 10000) + "\nBob,5,GBP,Lunch\n",
+			lines: []int{1, 2},
+			refs:  []string{strings.Repeat("x", 10000), "Lunch"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, done := writeTemp(t, tt.data)
+			defer done()
+			list, err := readBulkFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var lines []int
+			var refs []string
+			for _, l := range list {
+				lines = append(lines, l.n)
+				refs = append(refs, l.fields[3])
+			}
+			if !reflect.DeepEqual(lines, tt.lines) {
+				t.Errorf("lines %v, want %v", lines, tt.lines)
+			}
+			if !reflect.DeepEqual(refs, tt.refs) {
+				t.Errorf("references %q, want %q", refs, tt.refs)
+			}
+		})
+	}
+}
+
+func TestReadBulkFileColumns(t *testing.T) {
+	path, done := writeTemp(t, "Acme,10,GBP,Rent\n\nBob,5\n")
+	defer done()
+	_, err := readBulkFile(path)
+	if err == nil || !strings.HasPrefix(err.Error(), "line 3:") {
+		t.Errorf("got %v, want an error for line 3", err)
+	}
+}
+
+// bulkIDs loads the state file, assigns request IDs to two identical payments to Acme and another,
+// and returns the state and the IDs. Acme resolves to the counterparty ID cp.
+func bulkIDs(t *testing.T, path string, newBatch bool, cp string) (*bulkState, []string) {
+	t.Helper()
+	state, err := loadBulkState(path, newBatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := revolut.PaymentRequest{AccountID: "acc", Currency: "GBP", Amount: revolut.NewAmount(1000, 2), Reference: "Weekly"}
+	req.Receiver.CounterpartyID = cp
+	other := req
+	other.Reference = "Other"
+	weekly := []string{"Acme", "10", "GBP", "Weekly"}
+	list := []bulkPayment{
+		{line: 1, record: weekly, req: req},
+		{line: 2, record: weekly, req: req},
+		{line: 3, record: []string{"Acme", "10", "GBP", "Other"}, req: other},
+	}
+	err = assignBulkIDs(list, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, p := range list {
+		ids = append(ids, p.req.RequestID)
+	}
+	return state, ids
+}
+
+// recordSent appends the records a run writes for a payment to the state file: the request
+// before sending, and the transaction after, unless tx is empty.
+func recordSent(t *testing.T, path string, state *bulkState, p bulkPayment, tx string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+	err = state.start(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := bulkResult{RequestID: p.req.RequestID, Line: p.line, Record: p.record, Copy: p.copy, Time: time.Now()}
+	err = writeBulkResult(f, res)
+	if err == nil && tx != "" {
+		res.Transaction = tx
+		err = writeBulkResult(f, res)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBulkBatches(t *testing.T) {
+	path, done := writeTemp(t, "")
+	defer done()
+	os.Remove(path)
+	first, ids := bulkIDs(t, path, false, "cp")
+	if ids[0] == ids[1] || ids[0] == ids[2] {
+		t.Fatalf("payments share request IDs: %v", ids)
+	}
+
+	// Send the first payment, then stop as if the run crashed.
+	recordSent(t, path, first, bulkPayment{line: 1, record: []string{"Acme", "10", "GBP", "Weekly"}, req: revolut.PaymentRequest{RequestID: ids[0]}}, "tx1")
+	resumed, again := bulkIDs(t, path, false, "cp")
+	if resumed.Batch != first.Batch || !reflect.DeepEqual(again, ids) {
+		t.Errorf("resumed batch %s has IDs %v, want batch %s with %v", resumed.Batch, again, first.Batch, ids)
+	}
+	if resumed.Done[ids[0]] != "tx1" || len(resumed.Done) != 1 {
+		t.Errorf("resumed batch has done %v", resumed.Done)
+	}
+
+	next, fresh := bulkIDs(t, path, true, "cp")
+	if next.Batch == first.Batch || len(next.Done) != 0 {
+		t.Errorf("new batch %s has done %v", next.Batch, next.Done)
+	}
+	for i := range ids {
+		if fresh[i] == ids[i] {
+			t.Errorf("payment %d has the same ID %s in both batches", i+1, ids[i])
+		}
+	}
+
+	other, separate := bulkIDs(t, path+".other", false, "cp")
+	if other.Batch == first.Batch || separate[0] == ids[0] {
+		t.Errorf("separate runs share request ID %s", ids[0])
+	}
+}
+
+func TestBulkResumeAfterChange(t *testing.T) {
+	path, done := writeTemp(t, "")
+	defer done()
+	os.Remove(path)
+	first, ids := bulkIDs(t, path, false, "cp")
+
+	// The first Acme payment is sent and the second is sent without an answer, then the run crashes.
+	weekly := []string{"Acme", "10", "GBP", "Weekly"}
+	recordSent(t, path, first, bulkPayment{line: 1, record: weekly, req: revolut.PaymentRequest{RequestID: ids[0]}}, "tx1")
+	recordSent(t, path, first, bulkPayment{line: 2, record: weekly, copy: 1, req: revolut.PaymentRequest{RequestID: ids[1]}}, "")
+
+	// Acme is deleted and added again with a new ID before the re-run.
+	resumed, again := bulkIDs(t, path, false, "cp-new")
+	if again[0] != ids[0] || again[1] != ids[1] {
+		t.Errorf("recorded payments got IDs %v, want %v", again[:2], ids[:2])
+	}
+	if resumed.Done[again[0]] != "tx1" || resumed.Done[again[1]] != "" {
+		t.Errorf("resumed batch has done %v", resumed.Done)
+	}
+	if again[2] == ids[0] || again[2] == ids[1] {
+		t.Errorf("unsent payment got recorded ID %s", again[2])
+	}
+
+	// A different record resolving to what the first one did must not take its ID.
+	state, err := loadBulkState(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := revolut.PaymentRequest{AccountID: "acc", Currency: "GBP", Amount: revolut.NewAmount(1000, 2), Reference: "Weekly"}
+	req.Receiver.CounterpartyID = "cp"
+	list := []bulkPayment{{line: 1, record: []string{"cp", "10.00", "GBP", "Weekly"}, req: req}}
+	err = assignBulkIDs(list, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := list[0].req.RequestID; id == ids[0] || id == ids[1] {
+		t.Errorf("new record got recorded ID %s", id)
+	}
+}
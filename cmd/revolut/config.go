@@ -26,9 +26,9 @@ type Config struct {
 	ProductionKey string `json:"production_key"`
 	// SandboxKey is for testing and experimenting.
 	SandboxKey string `json:"sandbox_key"`
-	// LastRequest is a number to hash when generating payment request IDs.
-	LastRequest int64 `json:"last_request"`
-	UseSandbox  bool  `json:"usesandbox"`
+	UseSandbox bool   `json:"usesandbox"`
+	// ModulusTable is the path of a Vocalink valacdos.txt file to check UK account numbers with.
+	ModulusTable string `json:"modulus_table,omitempty"`
 }
 
 // CreateConfig creates a default configuration file which will need the API keys changed.
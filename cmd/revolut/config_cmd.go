@@ -4,6 +4,7 @@ package main
 import (
 	"errors"
 	"os"
+	"path/filepath"
 
 	"github.com/Urethramancer/revolut"
 	"github.com/Urethramancer/slog"
@@ -24,6 +25,7 @@ type SetConfigCmd struct {
 	SetProdKey SetProdKeyCmd `command:"prod" description:"Set production API key."`
 	SetSandKey SetSandKeyCmd `command:"sand" description:"Set sandbox API key."`
 	API        SetAPICmd     `command:"api" description:"Set the API to use."`
+	Modulus    SetModulusCmd `command:"modulus" description:"Set the UK modulus table file."`
 }
 
 // SetProdKeyCmd changes the production API key.
@@ -99,6 +101,38 @@ func (cmd *SetAPICmd) Execute(args []string) error {
 	return errors.New("unknown argument " + cmd.Args.API)
 }
 
+// SetModulusCmd sets the Vocalink modulus table used to check UK account numbers.
+type SetModulusCmd struct {
+	Args struct {
+		Path string `required:"true" positional-arg-name:"FILENAME" description:"Vocalink valacdos.txt file, or \"none\" to turn modulus checks off."`
+	} `positional-args:"true"`
+}
+
+// Execute the modulus table change.
+func (cmd *SetModulusCmd) Execute(args []string) error {
+	if cmd.Args.Path == "none" {
+		cfg.ModulusTable = ""
+		SaveConfig()
+		slog.Msg("UK account numbers will only be checked for format.")
+		return nil
+	}
+
+	path, err := filepath.Abs(cmd.Args.Path)
+	if err != nil {
+		return err
+	}
+
+	err = revolut.LoadUKModulusTable(path)
+	if err != nil {
+		return err
+	}
+
+	cfg.ModulusTable = path
+	SaveConfig()
+	slog.Msg("Loaded %d modulus rules from %s.", len(revolut.UKModulusTable), path)
+	return nil
+}
+
 //
 // View settings.
 //
@@ -108,6 +142,7 @@ type GetConfigCmd struct {
 	GetProdKey GetProdKeyCmd `command:"prod" description:"Show production API key."`
 	GetSandKey GetSandKeyCmd `command:"sand" description:"Show sandbox API key."`
 	API        GetAPICmd     `command:"api" description:"Show which API is used."`
+	Modulus    GetModulusCmd `command:"modulus" description:"Show the UK modulus table file."`
 }
 
 // GetProdKeyCmd shows the live API key.
@@ -140,3 +175,16 @@ func (cmd *GetAPICmd) Execute(args []string) error {
 	}
 	return nil
 }
+
+// GetModulusCmd shows the modulus table file.
+type GetModulusCmd struct{}
+
+// Execute the modulus table view.
+func (cmd *GetModulusCmd) Execute(args []string) error {
+	if cfg.ModulusTable == "" {
+		slog.Msg("No modulus table is set. UK account numbers are only checked for format.")
+	} else {
+		slog.Msg("%s", cfg.ModulusTable)
+	}
+	return nil
+}
@@ -82,7 +82,7 @@ func displayCounterparty(cp *revolut.Counterparty, short, details bool) {
 			id = shortUUID(id)
 		}
 		slog.Msg("\t%s (%s, %s)", id, acc.Type, acc.Currency)
-		if acc.Type == "external" {
+		if acc.Type == revolut.CounterpartyExternal {
 			slog.Msg("\t\t%s", acc.Name)
 			if len(acc.Account) > 0 {
 				slog.Msg("\t\tAccount no.: %s", acc.Account)
@@ -161,7 +161,7 @@ func (cmd *CPAddRevolutCmd) Execute(args []string) error {
 		if len(cmd.Email) == 0 {
 			return errors.New("e-mail is required for business accounts")
 		}
-		cp.ProfileType = "business"
+		cp.ProfileType = revolut.ProfileBusiness
 	} else {
 		if len(cmd.Name) == 0 {
 			return errors.New("a name is required for a personal account")
@@ -169,7 +169,7 @@ func (cmd *CPAddRevolutCmd) Execute(args []string) error {
 		if len(cmd.Phone) == 0 {
 			return errors.New("a phone number is required for a personal account")
 		}
-		cp.ProfileType = "personal"
+		cp.ProfileType = revolut.ProfilePersonal
 	}
 
 	c, err := newClient()
@@ -212,6 +212,18 @@ func (cmd *CPAddExternalCmd) Execute(args []string) error {
 		return err
 	}
 
+	if cfg.ModulusTable != "" {
+		err = revolut.LoadUKModulusTable(cfg.ModulusTable)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = cp.Validate()
+	if err != nil {
+		return validationFailed(err, "counterparty not added")
+	}
+
 	c, err := newClient()
 	if err != nil {
 		return err
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Urethramancer/revolut"
+	"github.com/Urethramancer/slog"
+)
+
+// ExchangeCmd exchanges money between your own accounts in different currencies.
+type ExchangeCmd struct {
+	ReferenceOption
+	RequestIDOption
+	Buy  bool `short:"b" long:"buy" description:"The amount is what to buy in the target currency, rather than what to sell."`
+	Yes  bool `short:"y" long:"yes" description:"Don't ask for confirmation after showing the quote."`
+	Args struct {
+		From   string `required:"true" positional-arg-name:"SOURCE ID" description:"UUID of account to sell from."`
+		To     string `required:"true" positional-arg-name:"DEST ID" description:"UUID of account to buy into."`
+		Amount string `required:"true" positional-arg-name:"AMOUNT" description:"Amount to sell, or to buy with --buy."`
+	} `positional-args:"true"`
+}
+
+// Execute the exchange.
+func (cmd *ExchangeCmd) Execute(args []string) error {
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	src, err := c.GetAccount(cmd.Args.From)
+	if err != nil {
+		return err
+	}
+
+	dst, err := c.GetAccount(cmd.Args.To)
+	if err != nil {
+		return err
+	}
+
+	req := revolut.ExchangeRequest{
+		From:      revolut.ExchangeSide{AccountID: src.ID, Currency: src.Currency},
+		To:        revolut.ExchangeSide{AccountID: dst.ID, Currency: dst.Currency},
+		Reference: cmd.Reference,
+	}
+
+	// Quotes are always for selling, so a purchase is quoted in reverse.
+	var rate *revolut.ExchangeRate
+	if cmd.Buy {
+		var amount revolut.Money
+		amount, err = revolut.ParseMoney(cmd.Args.Amount, dst.Currency)
+		if err != nil {
+			return err
+		}
+
+		req.To.Amount = &amount.Amount
+		rate, err = c.GetRate(dst.Currency, src.Currency, amount.Amount)
+		if err != nil {
+			return err
+		}
+
+		slog.Msg("Buying %s for about %s.", rate.From.Money(), rate.To.Money())
+	} else {
+		var amount revolut.Money
+		amount, err = revolut.ParseMoney(cmd.Args.Amount, src.Currency)
+		if err != nil {
+			return err
+		}
+
+		req.From.Amount = &amount.Amount
+		rate, err = c.GetRate(src.Currency, dst.Currency, amount.Amount)
+		if err != nil {
+			return err
+		}
+
+		slog.Msg("Selling %s for %s.", rate.From.Money(), rate.To.Money())
+	}
+
+	rateText, fee, err := describeQuote(rate, cmd.Buy, src.Currency, dst.Currency)
+	if err != nil {
+		return err
+	}
+
+	slog.Msg("Rate: %s (as of %s)", rateText, rate.RateDate.Format("2006-01-02 15:04:05"))
+	if !fee.Amount.IsZero() {
+		slog.Msg("Fee: %s", fee)
+	}
+
+	if !cmd.Yes && !confirm("Exchange at this rate?") {
+		slog.Msg("Cancelled.")
+		return nil
+	}
+
+	req.RequestID, err = requestID(cmd.RequestID)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Exchange(req)
+	if err != nil {
+		return err
+	}
+
+	slog.Msg("Created exchange %s: %s", resp.ID, resp.State)
+	return nil
+}
+
+// describeQuote returns the rate from the sell currency to the buy currency, and the fee in the
+// sell currency. A purchase is quoted in reverse, so its rate is inverted and its fee converted.
+func describeQuote(rate *revolut.ExchangeRate, buy bool, sell, bought string) (string, revolut.Money, error) {
+	if !buy {
+		return fmt.Sprintf("1 %s = %s %s", sell, rate.Rate, bought), rate.Fee.Money(), nil
+	}
+
+	fee := rate.Fee.Money()
+	if fee.Currency != sell {
+		amount, err := rate.Rate.Convert(fee.Amount, revolut.CurrencyExponent(sell))
+		if err != nil {
+			return "", revolut.Money{}, err
+		}
+		fee = revolut.Money{Amount: amount, Currency: sell}
+	}
+
+	return fmt.Sprintf("1 %s = %s %s", sell, rate.Rate.Inverse(), bought), fee, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Urethramancer/revolut"
+)
+
+func TestDescribeQuote(t *testing.T) {
+	tests := []struct {
+		name string
+		rate revolut.ExchangeRate
+		buy  bool
+		text string
+		fee  string
+	}{
+		{
+			name: "sell",
+			rate: revolut.ExchangeRate{Rate: revolut.NewRate(125, 2), Fee: revolut.ExchangeAmount{Amount: revolut.NewAmount(100, 2), Currency: "GBP"}},
+			text: "1 GBP = 1.25 USD",
+			fee:  "1.00 GBP",
+		},
+		{
+			// Buying USD with GBP is quoted as selling USD for GBP, with the fee in USD.
+			name: "buy",
+			rate: revolut.ExchangeRate{Rate: revolut.NewRate(8, 1), Fee: revolut.ExchangeAmount{Amount: revolut.NewAmount(250, 2), Currency: "USD"}},
+			buy:  true,
+			text: "1 GBP = 1.250000 USD",
+			fee:  "2.00 GBP",
+		},
+	}
+
+	for _, tt := range tests {
+		text, fee, err := describeQuote(&tt.rate, tt.buy, "GBP", "USD")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text != tt.text || fee.String() != tt.fee {
+			t.Errorf("%s: got %q and %s, want %q and %s", tt.name, text, fee, tt.text, tt.fee)
+		}
+	}
+}
@@ -61,10 +61,7 @@ func (cmd *JSONCPCmd) Execute(args []string) error {
 			Country:  "GB",
 		},
 		AccountNo: "12345678",
-		SortCode:  "1242",
-		RoutingNo: "666",
-		IBAN:      "iban",
-		BIC:       "bic/swift",
+		SortCode:  "123456",
 	}
 
 	s, err := json.MarshalIndent(&cp, "", "\t")
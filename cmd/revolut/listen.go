@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/Urethramancer/revolut"
+	"github.com/Urethramancer/revolut/webhook"
+	"github.com/Urethramancer/slog"
+)
+
+// WebListenCmd runs a local receiver for webhook events, for development and debugging.
+type WebListenCmd struct {
+	ShortOption
+	JSONOption
+	Addr    string   `short:"a" long:"addr" description:"Address to listen on." default:":8080" value-name:"<HOST:PORT>"`
+	Path    string   `short:"p" long:"path" description:"URL path to receive events on." default:"/" value-name:"PATH"`
+	Cert    string   `long:"cert" description:"TLS certificate file. Serves HTTPS when given with --key." value-name:"FILE"`
+	Key     string   `long:"key" description:"TLS private key file." value-name:"FILE"`
+	Secret  []string `long:"secret" description:"Signing secret to verify events with. Repeat it during rotation." value-name:"SECRET"`
+	Forward string   `short:"f" long:"forward" description:"URL to forward each received event to." value-name:"URL"`
+}
+
+// Execute the listener. It runs until interrupted.
+func (cmd *WebListenCmd) Execute(args []string) error {
+	h := &webhook.Handler{
+		OnTransactionCreated:      cmd.created,
+		OnTransactionStateChanged: cmd.changed,
+		OnUnknown:                 cmd.unknown,
+	}
+	if len(cmd.Secret) > 0 {
+		h.Verifier = &webhook.Verifier{Secrets: cmd.Secret}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(cmd.Path, cmd.forwarder(h))
+	srv := &http.Server{Addr: cmd.Addr, Handler: mux}
+	if cmd.Cert != "" || cmd.Key != "" {
+		slog.Msg("Listening for events on https://%s%s", cmd.Addr, cmd.Path)
+		return srv.ListenAndServeTLS(cmd.Cert, cmd.Key)
+	}
+
+	slog.Msg("Listening for events on http://%s%s", cmd.Addr, cmd.Path)
+	return srv.ListenAndServe()
+}
+
+func (cmd *WebListenCmd) created(ctx context.Context, e revolut.TransactionCreatedEvent) error {
+	if cmd.JSON {
+		return printJSONLine(e)
+	}
+
+	id := e.Data.ID
+	if cmd.Short {
+		id = shortUUID(id)
+	}
+	slog.Msg("%s created %s (%s): %s", e.Timestamp.Format(time.RFC3339), id, e.Data.Type, e.Data.State)
+	for _, l := range e.Data.Legs {
+		slog.Msg("\t%s, %s", legMoney(l), l.Description)
+	}
+	return nil
+}
+
+func (cmd *WebListenCmd) changed(ctx context.Context, e revolut.TransactionChangedEvent) error {
+	if cmd.JSON {
+		return printJSONLine(e)
+	}
+
+	id := e.Data.ID
+	if cmd.Short {
+		id = shortUUID(id)
+	}
+	slog.Msg("%s changed %s: %s -> %s", e.Timestamp.Format(time.RFC3339), id, e.Data.OldState, e.Data.NewState)
+	return nil
+}
+
+func (cmd *WebListenCmd) unknown(ctx context.Context, env webhook.Envelope, body []byte) error {
+	if cmd.JSON {
+		return printJSONLine(json.RawMessage(body))
+	}
+
+	slog.Msg("%s %s: %s", env.Timestamp.Format(time.RFC3339), env.Event, env.Data)
+	return nil
+}
+
+// forwarder passes each request to h, then posts the body on to the forwarding URL, if any.
+// Only events h accepted with a 2xx status are forwarded, so forged, malformed and failed
+// ones are not. The signature headers are kept, so the receiver can verify the events too.
+func (cmd *WebListenCmd) forwarder(h http.Handler) http.Handler {
+	if cmd.Forward == "" {
+		return h
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, webhook.DefaultMaxBodySize))
+		if err != nil {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(sw, r)
+		if r.Method == http.MethodPost && sw.status >= 200 && sw.status < 300 {
+			go cmd.forward(body, r.Header.Clone())
+		}
+	})
+}
+
+// statusWriter is a ResponseWriter which remembers the status code written.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status and writes it.
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// forward posts one event body.
+func (cmd *WebListenCmd) forward(body []byte, header http.Header) {
+	req, err := http.NewRequest(http.MethodPost, cmd.Forward, bytes.NewReader(body))
+	if err != nil {
+		slog.Error("Error forwarding event: %s", err.Error())
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for _, k := range []string{webhook.SignatureHeader, webhook.TimestampHeader} {
+		if v := header.Get(k); v != "" {
+			req.Header.Set(k, v)
+		}
+	}
+
+	client := http.Client{Timeout: time.Second * 10}
+	res, err := client.Do(req)
+	if err != nil {
+		slog.Error("Error forwarding event: %s", err.Error())
+		return
+	}
+
+	res.Body.Close()
+	if res.StatusCode >= 300 {
+		slog.Warn("Forwarded event was answered with %s", res.Status)
+	}
+}
+
+// printJSONLine prints v as compact JSON on one line.
+func printJSONLine(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	err = json.Compact(&buf, data)
+	if err != nil {
+		return err
+	}
+
+	slog.Msg("%s", buf.String())
+	return nil
+}
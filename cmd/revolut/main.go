@@ -15,6 +15,7 @@ var O struct {
 	Account      AccountCmd      `command:"account" alias:"acc" description:"Account details."`
 	Counterparty CounterpartyCmd `command:"counterparty" alias:"cp" description:"Counterparty listing and management."`
 	Transfer     TransferCmd     `command:"transfer" alias:"tr" description:"Transfer between your own accounts."`
+	Exchange     ExchangeCmd     `command:"exchange" alias:"fx" description:"Exchange between your own accounts in different currencies."`
 	Payment      PaymentCmd      `command:"payments" alias:"pay" description:"Payments and transactions."`
 	Webhook      WebhookCmd      `command:"webhooks" alias:"web" description:"Webhook listing and management."`
 	JSON         JSONCmd         `command:"json" description:"Print example data structures for JSON input."`
@@ -26,6 +26,16 @@ type JSONOption struct {
 	JSON bool `short:"j" long:"json" description:"Print the actual JSON structure instead of formatted information."`
 }
 
+// DryRunOption checks a transaction against your accounts and counterparties without sending it.
+type DryRunOption struct {
+	DryRun bool `short:"n" long:"dry-run" description:"Check the accounts, currency and balance, but don't send anything."`
+}
+
+// RequestIDOption lets a transaction be repeated safely with the same request ID.
+type RequestIDOption struct {
+	RequestID string `long:"request-id" description:"Request ID to send. Running the command again with the same ID won't repeat the transaction. A new UUID is used if unspecified." value-name:"ID"`
+}
+
 // ReferenceOption is used on transactions from your accounts.
 type ReferenceOption struct {
 	Reference string `short:"r" long:"reference" descripttion:"Optional reference to show on the transaction." value-name:"TEXT"`
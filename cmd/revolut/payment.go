@@ -1,8 +1,9 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"strings"
+	"time"
 
 	"github.com/Urethramancer/revolut"
 	"github.com/Urethramancer/slog"
@@ -18,6 +19,10 @@ type PaymentCmd struct {
 	Show PayShowCmd `command:"show" alias:"status" description:"Show the status of a payment."`
 	// Cancel a transaction
 	Cancel PayCancelCmd `command:"cancel" description:"Cancel a scheduled payment, if possible."`
+	// Pay from a file
+	Bulk PayBulkCmd `command:"bulk" description:"Pay many counterparties from a CSV file, showing a summary first. A re-run skips payments already sent."`
+	// Wait for completion
+	Wait PayWaitCmd `command:"wait" description:"Wait for payments to complete, fail or be declined, showing each state change."`
 }
 
 // PayListCmd shows payments and/or internal transactions.
@@ -30,17 +35,45 @@ type PayListCmd struct {
 	To string `short:"e" long:"to" description:"To date. Use YYYY-MM-DD or RFC3339." value-name:"<ISO DATE>"`
 	// Counterparty UUID
 	Counterparty string `short:"c" long:"counterparty" description:"UUID of counterparty to show transfers for." value-name:"<UUID>"`
+	// Account UUID
+	Account string `short:"a" long:"account" description:"UUID of your account to show transfers for." value-name:"<UUID>"`
 	// Max transactions to show
-	Max int64 `short:"m" long:"max" description:"Maximum transactions to show." default:"100" value-name:"<NUMBER>"`
+	Max int `short:"m" long:"max" description:"Maximum transactions to show." default:"100" value-name:"<NUMBER>"`
+	// All pages of history
+	All bool `long:"all" description:"Page through the full history instead of stopping at --max. Each request fetches up to --max transactions."`
 	// Type of transactions to show
-	Type string `short:"t" long:"type" description:"Type of transactions to show." value-name:"<TYPE>" `
+	Type string `short:"t" long:"type" description:"Comma-separated list of transaction types to show." value-name:"<TYPE,...>" `
 }
 
 // Execute the transaction listing.
 func (cmd *PayListCmd) Execute(args []string) error {
-	if cmd.To != "" && !revolut.ValidTransactionType(cmd.Type) {
-		slog.Msg("Type must be one of atm, card_payment, card_refund, card_chargeback, card_credit, exchange, transfer, loan, fee, refund, topup, topup_return, tax or tax_refund.")
-		return nil
+	q := revolut.TransactionQuery{
+		Counterparty: cmd.Counterparty,
+		Account:      cmd.Account,
+		Count:        cmd.Max,
+	}
+	for _, s := range splitList(cmd.Type) {
+		t := revolut.TransactionType(s)
+		if !t.Known() {
+			slog.Msg("Type must be one of atm, card_payment, card_refund, card_chargeback, card_credit, exchange, transfer, loan, fee, refund, topup, topup_return, tax or tax_refund.")
+			return nil
+		}
+		q.Types = append(q.Types, t)
+	}
+
+	var err error
+	if cmd.From != "" {
+		q.From, err = revolut.ParseTime(cmd.From)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmd.To != "" {
+		q.To, err = revolut.ParseTime(cmd.To)
+		if err != nil {
+			return err
+		}
 	}
 
 	c, err := newClient()
@@ -48,7 +81,20 @@ func (cmd *PayListCmd) Execute(args []string) error {
 		return err
 	}
 
-	tr, err := c.GetTransactions(cmd.Type, cmd.From, cmd.To, cmd.Counterparty, cmd.Max)
+	if cmd.All {
+		n := 0
+		err = c.IterateTransactions(context.Background(), q, func(t revolut.TransactionStatus) error {
+			n++
+			displayTransaction(t, cmd.Short, cmd.Details)
+			return nil
+		})
+		if err == nil && n == 0 {
+			slog.Msg("No transactions to show.")
+		}
+		return err
+	}
+
+	tr, err := c.ListTransactions(context.Background(), q)
 	if err != nil {
 		return err
 	}
@@ -71,7 +117,7 @@ func displayTransaction(t revolut.TransactionStatus, short, details bool) {
 	}
 
 	legs := len(t.Legs)
-	slog.Msg("%s (%s), %d leg(s), %s: %.2f %s, %s", id, t.Type, legs, t.State, t.Legs[0].Amount, t.Legs[0].Currency, t.Legs[0].Description)
+	slog.Msg("%s (%s), %d leg(s), %s: %s, %s", id, t.Type, legs, t.State, legMoney(t.Legs[0]), t.Legs[0].Description)
 	if details {
 		for _, l := range t.Legs {
 			lid := l.ID
@@ -79,24 +125,30 @@ func displayTransaction(t revolut.TransactionStatus, short, details bool) {
 				lid = shortUUID(lid)
 			}
 			alt := ""
-			if l.BillAmount != 0 {
-				alt = fmt.Sprintf(" (%.2f %s)", l.BillAmount, l.BillCurrency)
+			if !l.BillAmount.IsZero() {
+				alt = fmt.Sprintf(" (%s)", revolut.Money{Amount: l.BillAmount, Currency: l.BillCurrency})
 			}
-			slog.Msg("\t%s: %.2f %s%s, %s", lid, l.Amount, l.Currency, alt, l.Description)
+			slog.Msg("\t%s: %s%s, %s", lid, legMoney(l), alt, l.Description)
 		}
 	}
 }
 
+func legMoney(l revolut.Leg) revolut.Money {
+	return revolut.Money{Amount: l.Amount, Currency: l.Currency}
+}
+
 // PaySendCmd sends money to counterparties.
 type PaySendCmd struct {
 	ReferenceOption
+	RequestIDOption
+	DryRunOption
 	RecAccount   string `short:"a" long:"account" description:"Counterparty account, if necessary. This isn't required for Revolut counterparties." value-name:"ACCOUNT"`
 	ScheduleTime string `short:"s" long:"schedule" description:"Scheduled time to start the payment. Use YYYY-MM-DD or ISO3339." value-name:"TIME"`
 	Args         struct {
-		Account      string  `required:"true" positional-arg-name:"ACCOUNT" description:"UUID of the  account to pay from."`
-		Counterparty string  `required:"true" positional-arg-name:"COUNTERPARTY" description:"UUID of the receiving counterparty."`
-		Amount       float64 `required:"true" positional-arg-name:"AMOUNT" description:"Amount to transfer."`
-		Currency     string  `required:"true" positional-arg-name:"CURRENCY" description:"Currency to transfer in."`
+		Account      string `required:"true" positional-arg-name:"ACCOUNT" description:"UUID of the  account to pay from."`
+		Counterparty string `required:"true" positional-arg-name:"COUNTERPARTY" description:"UUID of the receiving counterparty."`
+		Amount       string `required:"true" positional-arg-name:"AMOUNT" description:"Amount to transfer."`
+		Currency     string `required:"true" positional-arg-name:"CURRENCY" description:"Currency to transfer in."`
 	} `positional-args:"true"`
 }
 
@@ -107,9 +159,36 @@ func (cmd *PaySendCmd) Execute(args []string) error {
 		return err
 	}
 
-	id := generateRequestID()
-	slog.Msg("Paying %.2f %s with ID %s.", cmd.Args.Amount, strings.ToUpper(cmd.Args.Currency), id)
-	resp, err := c.Pay(id, cmd.Args.Account, cmd.Args.Counterparty, cmd.RecAccount, cmd.Args.Currency, cmd.Reference, cmd.ScheduleTime, cmd.Args.Amount)
+	amount, err := revolut.ParseMoney(cmd.Args.Amount, cmd.Args.Currency)
+	if err != nil {
+		return err
+	}
+
+	if cmd.DryRun {
+		req := revolut.PaymentRequest{
+			AccountID:    cmd.Args.Account,
+			Receiver:     revolut.Receiver{CounterpartyID: cmd.Args.Counterparty, AccountID: cmd.RecAccount},
+			Amount:       amount.Amount,
+			Currency:     amount.Currency,
+			Reference:    cmd.Reference,
+			ScheduleTime: cmd.ScheduleTime,
+		}
+		err = req.Validate(context.Background(), c)
+		if err != nil {
+			return validationFailed(err, "payment would fail")
+		}
+
+		slog.Msg("Payment of %s looks fine. Nothing was sent.", amount)
+		return nil
+	}
+
+	id, err := requestID(cmd.RequestID)
+	if err != nil {
+		return err
+	}
+
+	slog.Msg("Paying %s with ID %s.", amount, id)
+	resp, err := c.Pay(id, cmd.Args.Account, cmd.Args.Counterparty, cmd.RecAccount, amount.Currency, cmd.Reference, cmd.ScheduleTime, amount.Amount)
 	if err != nil {
 		return err
 	}
@@ -159,3 +238,49 @@ func (cmd *PayCancelCmd) Execute(args []string) error {
 
 	return c.CancelPayment(cmd.Args.ID)
 }
+
+// PayWaitCmd waits for payments to finish.
+type PayWaitCmd struct {
+	ShortOption
+	Interval time.Duration `short:"i" long:"interval" description:"Initial time between status checks. It backs off while nothing changes." default:"2s" value-name:"DURATION"`
+	Timeout  time.Duration `short:"t" long:"timeout" description:"Give up after this long. 0 waits forever." default:"0" value-name:"DURATION"`
+	Args     struct {
+		IDs []string `required:"1" positional-arg-name:"TRANSACTION" description:"UUIDs of transactions to wait for."`
+	} `positional-args:"true"`
+}
+
+// Execute the wait.
+func (cmd *PayWaitCmd) Execute(args []string) error {
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if cmd.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cmd.Timeout)
+		defer cancel()
+	}
+
+	w := c.NewWatcher(revolut.WaitOptions{Interval: cmd.Interval})
+	w.Add(cmd.Args.IDs...)
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Run(ctx)
+	}()
+
+	for ch := range w.Changes() {
+		id := ch.ID
+		if cmd.Short {
+			id = shortUUID(id)
+		}
+
+		if ch.OldState == "" {
+			slog.Msg("%s: %s", id, ch.NewState)
+		} else {
+			slog.Msg("%s: %s -> %s", id, ch.OldState, ch.NewState)
+		}
+	}
+	return <-done
+}
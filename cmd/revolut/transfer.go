@@ -1,19 +1,22 @@
 package main
 
 import (
-	"strings"
+	"context"
 
+	"github.com/Urethramancer/revolut"
 	"github.com/Urethramancer/slog"
 )
 
 // TransferCmd transfers money between your own Revolut for Business accounts.
 type TransferCmd struct {
 	ReferenceOption
+	RequestIDOption
+	DryRunOption
 	Args struct {
-		From     string  `required:"true" positional-arg-name:"SOURCE ID" description:"UUID of account to transfer from."`
-		To       string  `required:"true" positional-arg-name:"DEST ID" description:"UUID of account to transfer to."`
-		Amount   float64 `required:"true" positional-arg-name:"AMOUNT" description:"Amount to transfer."`
-		Currency string  `required:"true" positional-arg-name:"CURRENCY" description:"Currency to transfer in."`
+		From     string `required:"true" positional-arg-name:"SOURCE ID" description:"UUID of account to transfer from."`
+		To       string `required:"true" positional-arg-name:"DEST ID" description:"UUID of account to transfer to."`
+		Amount   string `required:"true" positional-arg-name:"AMOUNT" description:"Amount to transfer."`
+		Currency string `required:"true" positional-arg-name:"CURRENCY" description:"Currency to transfer in."`
 	} `positional-args:"true"`
 }
 
@@ -24,9 +27,35 @@ func (cmd *TransferCmd) Execute(args []string) error {
 		return err
 	}
 
-	id := generateRequestID()
-	slog.Msg("Transferring %.2f %s with ID %s.", cmd.Args.Amount, strings.ToUpper(cmd.Args.Currency), id)
-	resp, err := c.Transfer(id, cmd.Args.From, cmd.Args.To, cmd.Args.Currency, cmd.Reference, cmd.Args.Amount)
+	amount, err := revolut.ParseMoney(cmd.Args.Amount, cmd.Args.Currency)
+	if err != nil {
+		return err
+	}
+
+	if cmd.DryRun {
+		req := revolut.TransferRequest{
+			SourceID:  cmd.Args.From,
+			TargetID:  cmd.Args.To,
+			Amount:    amount.Amount,
+			Currency:  amount.Currency,
+			Reference: cmd.Reference,
+		}
+		err = req.Validate(context.Background(), c)
+		if err != nil {
+			return validationFailed(err, "transfer would fail")
+		}
+
+		slog.Msg("Transfer of %s looks fine. Nothing was sent.", amount)
+		return nil
+	}
+
+	id, err := requestID(cmd.RequestID)
+	if err != nil {
+		return err
+	}
+
+	slog.Msg("Transferring %s with ID %s.", amount, id)
+	resp, err := c.Transfer(id, cmd.Args.From, cmd.Args.To, amount.Currency, cmd.Reference, amount.Amount)
 	if err != nil {
 		return err
 	}
@@ -1,12 +1,14 @@
 package main
 
 import (
-	"crypto/sha1"
+	"bufio"
+	"errors"
 	"fmt"
-	"io"
+	"os"
 	"strings"
 
 	"github.com/Urethramancer/revolut"
+	"github.com/Urethramancer/slog"
 )
 
 // newClient wraps the NewClient() method in the Revolut SDK to select the configured key.
@@ -31,6 +33,18 @@ func shortUUID(id string) string {
 	return a[len(a)-1]
 }
 
+// splitList splits a comma-separated list, trimming spaces and dropping empty elements.
+func splitList(list string) []string {
+	var l []string
+	for _, s := range strings.Split(list, ",") {
+		s = strings.TrimSpace(s)
+		if s != "" {
+			l = append(l, s)
+		}
+	}
+	return l
+}
+
 // shouldDisplayCurrency checks if the given currency is in the list.
 func shouldDisplayCurrency(currency, list string) bool {
 	if len(list) == 0 {
@@ -47,15 +61,41 @@ func shouldDisplayCurrency(currency, list string) bool {
 	return false
 }
 
-// generateRequestID returns a string based on the last request ID in the configuration.
-// We're just going for a boring, old SHA1 hash. It could easily be replaced with any hash,
-// but this should be sufficient for uniqueness within one user's payments.
-func generateRequestID() string {
-	cfg.LastRequest++
-	SaveConfig()
-	id := fmt.Sprintf("%s%d", programName, cfg.LastRequest)
-	h := sha1.New()
-	io.WriteString(h, id)
-	id = fmt.Sprintf("%x", h.Sum(nil))
-	return id
+// confirm asks a yes/no question on the terminal. Anything but yes is a no.
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// requestID returns the request ID given on the command line, or a new UUID.
+// Version 7 UUIDs are unique across machines sharing a key, and sort by time in logs.
+func requestID(id string) (string, error) {
+	if id == "" {
+		return revolut.UUIDv7{}.RequestID(revolut.RequestDetails{})
+	}
+
+	if len(id) > revolut.MaxRequestIDLength {
+		return "", fmt.Errorf("request ID is longer than %d characters", revolut.MaxRequestIDLength)
+	}
+
+	return id, nil
+}
+
+// validationFailed prints each problem in a ValidationError and returns a short error saying
+// what didn't happen. Other errors are returned as they are.
+func validationFailed(err error, what string) error {
+	var ve revolut.ValidationError
+	if !errors.As(err, &ve) {
+		return err
+	}
+
+	for _, fe := range ve {
+		slog.Error("%s", fe.Error())
+	}
+	return errors.New(what)
 }
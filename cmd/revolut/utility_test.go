@@ -0,0 +1,26 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"TransactionCreated", []string{"TransactionCreated"}},
+		{"TransactionCreated, TransactionStateChanged", []string{"TransactionCreated", "TransactionStateChanged"}},
+		{" TransactionCreated ,,TransactionStateChanged, ", []string{"TransactionCreated", "TransactionStateChanged"}},
+		{" , ", nil},
+	}
+
+	for _, tt := range tests {
+		got := splitList(tt.in)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitList(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -1,21 +1,127 @@
 package main
 
-import "github.com/Urethramancer/slog"
+import (
+	"strings"
 
-// WebhookCmd adds a webhook for callback triggering.
+	"github.com/Urethramancer/slog"
+)
+
+// WebhookCmd holds tool commands for webhook listing and management.
 type WebhookCmd struct {
+	List   WebListCmd   `command:"list" alias:"ls" description:"List webhooks."`
+	Add    WebAddCmd    `command:"add" description:"Add a webhook for callback triggering."`
+	Update WebUpdateCmd `command:"update" alias:"up" description:"Change the URL or events of a webhook."`
+	Delete WebDeleteCmd `command:"delete" alias:"del" alias:"rm" description:"Delete a webhook."`
+	Listen WebListenCmd `command:"listen" description:"Receive webhook events locally and print them."`
+}
+
+// WebListCmd lists webhooks.
+type WebListCmd struct{}
+
+// Execute the webhook listing.
+func (cmd *WebListCmd) Execute(args []string) error {
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	list, err := c.ListWebhooks()
+	if err != nil {
+		return err
+	}
+
+	if len(list) == 0 {
+		slog.Msg("No webhooks to list.")
+		return nil
+	}
+
+	for _, hook := range list {
+		events := "all events"
+		if len(hook.Events) > 0 {
+			events = strings.Join(hook.Events, ", ")
+		}
+		slog.Msg("%s: %s (%s)", hook.ID, hook.URL, events)
+	}
+	return nil
+}
+
+// WebAddCmd adds a webhook.
+type WebAddCmd struct {
 	Args struct {
 		URL string `required:"true" positional-arg-name:"URL" description:"URL of webhook to add."`
 	} `positional-args:"true"`
 }
 
 // Execute the webhook addition.
-func (cmd *WebhookCmd) Execute(args []string) error {
+func (cmd *WebAddCmd) Execute(args []string) error {
 	c, err := newClient()
 	if err != nil {
 		return err
 	}
 
-	slog.Msg("Webhook added.")
-	return c.AddWebhook(cmd.Args.URL)
+	hook, err := c.AddWebhook(cmd.Args.URL)
+	if err != nil {
+		return err
+	}
+
+	slog.Msg("Webhook %s added: %s", hook.ID, hook.URL)
+	if hook.SigningSecret != "" {
+		slog.Msg("Signing secret: %s", hook.SigningSecret)
+	}
+	return nil
+}
+
+// WebUpdateCmd changes a webhook.
+type WebUpdateCmd struct {
+	URL    string `short:"u" long:"url" description:"New URL for the webhook." value-name:"URL"`
+	Events string `short:"e" long:"events" description:"Comma-separated list of events to subscribe to." value-name:"<EVENT,...>"`
+	Args   struct {
+		ID string `required:"true" positional-arg-name:"ID" description:"ID of webhook to change."`
+	} `positional-args:"true"`
+}
+
+// Execute the webhook update.
+func (cmd *WebUpdateCmd) Execute(args []string) error {
+	events := splitList(cmd.Events)
+
+	if cmd.URL == "" && len(events) == 0 {
+		slog.Msg("Nothing to change. Specify a new URL and/or events.")
+		return nil
+	}
+
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	hook, err := c.UpdateWebhook(cmd.Args.ID, cmd.URL, events)
+	if err != nil {
+		return err
+	}
+
+	slog.Msg("Webhook %s updated: %s", hook.ID, hook.URL)
+	return nil
+}
+
+// WebDeleteCmd deletes a webhook.
+type WebDeleteCmd struct {
+	Args struct {
+		ID string `required:"true" positional-arg-name:"ID" description:"ID of webhook to delete."`
+	} `positional-args:"true"`
+}
+
+// Execute the removal.
+func (cmd *WebDeleteCmd) Execute(args []string) error {
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	err = c.DeleteWebhook(cmd.Args.ID)
+	if err != nil {
+		return err
+	}
+
+	slog.Msg("Webhook deleted.")
+	return nil
 }
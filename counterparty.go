@@ -1,9 +1,8 @@
 package revolut
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
-	"time"
 )
 
 // Counterparty is returned from the /counterparty and /counterparties endpoints.
@@ -15,15 +14,15 @@ type Counterparty struct {
 	// Phone number.
 	Phone string `json:"phone"`
 	// Type is "personal" or "business".
-	Type string `json:"profile_type"`
+	Type ProfileType `json:"profile_type"`
 	// Country is a two-letter ISO code.
 	Country string `json:"country"`
-	// State of the counterparty is a status string.
-	State string `json:"state"`
+	// State of the counterparty.
+	State CounterpartyState `json:"state"`
 	// CreatedAt is a timestamp for when this was added.
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt Time `json:"created_at"`
 	// UpdatedAt is a timestamp for the last change to the counterparty,
-	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedAt Time `json:"updated_at"`
 	// Accounts is a list of public accounts for this counterparty.
 	Accounts []CounterpartyAccount `json:"accounts"`
 }
@@ -35,7 +34,7 @@ type CounterpartyAccount struct {
 	// Currency is a three-letter shortname.
 	Currency string `json:"currency"`
 	// Type of account is either "revolut" or "external".
-	Type string `json:"type"`
+	Type CounterpartyType `json:"type"`
 	// Account number.
 	Account string `json:"account_no"`
 	// SortCode if used.
@@ -53,7 +52,7 @@ type CounterpartyAccount struct {
 // InternalCounterparty is used when adding an existing Revolut account as a counterparty (i.e. contact).
 type InternalCounterparty struct {
 	// ProfileType is "business" or "personal".
-	ProfileType string `json:"profile_type"`
+	ProfileType ProfileType `json:"profile_type"`
 	// Name of the counterparty.
 	Name string `json:"name,omitempty"`
 	// Phone is used with personal accounts.
@@ -71,15 +70,15 @@ type CounterpartyResponse struct {
 	// Phone number of a personal account.
 	Phone string `json:"phone"`
 	// ProfileType is "business" or "personal".
-	ProfileType string `json:"profile_type"`
+	ProfileType ProfileType `json:"profile_type"`
 	// Country is a 2-letter code.
 	Country string `json:"bank_country"`
 	// State is either "created" or "deleted".
-	State string `json:"state"`
+	State CounterpartyState `json:"state"`
 	// CreatedAt is the ISO time when the counterparty was created.
-	CreatedAt string `json:"created_at"`
+	CreatedAt Time `json:"created_at"`
 	// UpdateAt is the ISO time when the counterparty was last updated.
-	UpdatedAt string `json:"updated_at"`
+	UpdatedAt Time `json:"updated_at"`
 	// Accounts is a list of all the counterparty's accounts.
 	Accounts []Account `json:"accounts"`
 }
@@ -112,6 +111,51 @@ type ExternalCounterparty struct {
 	BIC string `json:"bic,omitempty"`
 }
 
+// Validate checks the name and ISO codes, that the bank details required by the RuleFor the
+// country and currency are present and no others are, and the format of each bank detail.
+// It returns a ValidationError listing every problem.
+func (cp ExternalCounterparty) Validate() error {
+	var ve ValidationError
+	if cp.Company == "" && (cp.Name == nil || cp.Name.First == "" && cp.Name.Last == "") {
+		ve.add("company_name", ErrMissingName)
+	}
+
+	cerr := ValidateCountry(cp.BankCountry)
+	ve.add("bank_country", cerr)
+	err := ValidateCurrency(cp.Currency)
+	ve.add("currency", err)
+	if cerr == nil && err == nil {
+		RuleFor(cp.BankCountry, cp.Currency).check(cp.bankFields(), &ve)
+	}
+
+	if cp.IBAN != "" {
+		ve.add("iban", ValidateIBAN(cp.IBAN))
+	}
+
+	if cp.BIC != "" {
+		ve.add("bic", ValidateBIC(cp.BIC))
+	}
+
+	if cp.RoutingNo != "" {
+		ve.add("routing_number", ValidateRoutingNumber(cp.RoutingNo))
+	}
+
+	// Account numbers elsewhere have other formats, so only UK ones are checked against the sort code.
+	switch {
+	case cp.BankCountry == "GB" && cp.SortCode != "" && cp.AccountNo != "":
+		err = ValidateUKAccount(cp.SortCode, cp.AccountNo)
+		if errors.Is(err, ErrSortCode) {
+			ve.add("sort_code", err)
+		} else {
+			ve.add("account_no", err)
+		}
+	case cp.SortCode != "":
+		ve.add("sort_code", ValidateSortCode(cp.SortCode))
+	}
+
+	return ve.err()
+}
+
 // IndividualName of an account holder.
 type IndividualName struct {
 	// First name.
@@ -127,11 +171,11 @@ type ExternalCounterpartyResponse struct {
 	// Name
 	Name string `json:"name"`
 	// State is either "created" or "deleted".
-	State string `json:"state"`
+	State CounterpartyState `json:"state"`
 	// CreatedAt is the ISO time/date this counterparty was created.
-	CreatedAt string `json:"created_at"`
+	CreatedAt Time `json:"created_at"`
 	// UpdatedAt is the ISO time/date this counterparty was last modified.
-	UpdatedAt string `json:"updated_at"`
+	UpdatedAt Time `json:"updated_at"`
 	// Accounts known for this counterparty.
 	Accounts []ExternalAccount `json:"accounts"`
 }
@@ -143,7 +187,7 @@ type ExternalAccount struct {
 	// Currency is a 3-letter ISO code.
 	Currency string `json:"currency"`
 	// Type is "revolut" or "external".
-	Type string `json:"type"`
+	Type CounterpartyType `json:"type"`
 	// AccountNo for UK GBP, US USD and SWIFT accounts
 	AccountNo string `json:"account_no"`
 	// IBAN of a foreign account.
@@ -160,19 +204,13 @@ type ExternalAccount struct {
 
 // GetCounterparties returns a list of all counterparties for an API key.
 func (c *Client) GetCounterparties() ([]Counterparty, error) {
-	contents, code, err := c.GetJSON(epCounterparties)
-	c.ErrorCode = code
-	if err != nil {
-		return nil, err
-	}
-
-	if code != 200 {
-		err = errors.New(codeToError(code))
-		return nil, err
-	}
+	return c.GetCounterpartiesContext(context.Background())
+}
 
+// GetCounterpartiesContext is GetCounterparties with a context for deadlines and cancellation.
+func (c *Client) GetCounterpartiesContext(ctx context.Context) ([]Counterparty, error) {
 	var data []Counterparty
-	err = json.Unmarshal(contents, &data)
+	err := c.do(ctx, "GET", epCounterparties, nil, &data)
 	if err != nil {
 		return nil, err
 	}
@@ -182,19 +220,13 @@ func (c *Client) GetCounterparties() ([]Counterparty, error) {
 
 // GetCounterparty gets a counterparty by ID.
 func (c *Client) GetCounterparty(id string) (*Counterparty, error) {
-	contents, code, err := c.GetJSON(epCounterparty + "/" + id)
-	c.ErrorCode = code
-	if err != nil {
-		return nil, err
-	}
-
-	if code != 200 {
-		err = errors.New(codeToError(code))
-		return nil, err
-	}
+	return c.GetCounterpartyContext(context.Background(), id)
+}
 
+// GetCounterpartyContext is GetCounterparty with a context for deadlines and cancellation.
+func (c *Client) GetCounterpartyContext(ctx context.Context, id string) (*Counterparty, error) {
 	var data Counterparty
-	err = json.Unmarshal(contents, &data)
+	err := c.do(ctx, "GET", epCounterparty+"/"+id, nil, &data)
 	if err != nil {
 		return nil, err
 	}
@@ -204,49 +236,48 @@ func (c *Client) GetCounterparty(id string) (*Counterparty, error) {
 
 // AddRevolutCounterparty adds a Revolut personal or business account as a counterparty.
 func (c *Client) AddRevolutCounterparty(cp InternalCounterparty) (*CounterpartyResponse, error) {
-	contents, code, err := c.PostJSON(epCounterparty, cp)
-	c.ErrorCode = code
+	return c.AddRevolutCounterpartyContext(context.Background(), cp)
+}
+
+// AddRevolutCounterpartyContext is AddRevolutCounterparty with a context for deadlines and cancellation.
+func (c *Client) AddRevolutCounterpartyContext(ctx context.Context, cp InternalCounterparty) (*CounterpartyResponse, error) {
+	var res CounterpartyResponse
+	err := c.do(ctx, "POST", epCounterparty, cp, &res)
 	if err != nil {
 		return nil, err
 	}
 
-	if code != 200 {
-		return nil, jsonError(contents)
-	}
-
-	var res CounterpartyResponse
-	err = json.Unmarshal(contents, &res)
-	return &res, err
+	return &res, nil
 }
 
 // AddExternalCounterparty adds a non-Revolut account as a counterparty.
+// The details are checked with Validate first, and nothing is sent if they fail.
 func (c *Client) AddExternalCounterparty(cp ExternalCounterparty) (*ExternalCounterpartyResponse, error) {
-	contents, code, err := c.PostJSON(epCounterparty, cp)
-	c.ErrorCode = code
+	return c.AddExternalCounterpartyContext(context.Background(), cp)
+}
+
+// AddExternalCounterpartyContext is AddExternalCounterparty with a context for deadlines and cancellation.
+func (c *Client) AddExternalCounterpartyContext(ctx context.Context, cp ExternalCounterparty) (*ExternalCounterpartyResponse, error) {
+	err := cp.Validate()
 	if err != nil {
 		return nil, err
 	}
 
-	if code != 200 {
-		return nil, jsonError(contents)
+	var res ExternalCounterpartyResponse
+	err = c.do(ctx, "POST", epCounterparty, cp, &res)
+	if err != nil {
+		return nil, err
 	}
 
-	var res ExternalCounterpartyResponse
-	err = json.Unmarshal(contents, &res)
-	return &res, err
+	return &res, nil
 }
 
 // DeleteCounterparty removes a counterparty by UUID.
 func (c *Client) DeleteCounterparty(id string) error {
-	contents, code, err := c.Delete(epCounterparty + "/" + id)
-	c.ErrorCode = code
-	if err != nil {
-		return err
-	}
-
-	if code != 204 {
-		return jsonError(contents)
-	}
+	return c.DeleteCounterpartyContext(context.Background(), id)
+}
 
-	return nil
+// DeleteCounterpartyContext is DeleteCounterparty with a context for deadlines and cancellation.
+func (c *Client) DeleteCounterpartyContext(ctx context.Context, id string) error {
+	return c.do(ctx, "DELETE", epCounterparty+"/"+id, nil, nil)
 }
@@ -0,0 +1,39 @@
+package revolut
+
+import "testing"
+
+func TestExternalCounterpartyValidate(t *testing.T) {
+	gb := ExternalCounterparty{Company: "Acme", BankCountry: "GB", Currency: "GBP", SortCode: "08-99-99", AccountNo: "66374958"}
+	de := ExternalCounterparty{Company: "Acme", BankCountry: "DE", Currency: "EUR", IBAN: "DE89370400440532013000", BIC: "DEUTDEFF"}
+	us := ExternalCounterparty{Company: "Acme", BankCountry: "US", Currency: "USD", AccountNo: "12345678", RoutingNo: "021000021"}
+
+	tests := []struct {
+		name  string
+		cp    ExternalCounterparty
+		edit  func(*ExternalCounterparty)
+		field string
+		want  error
+	}{
+		{"UK", gb, func(cp *ExternalCounterparty) {}, "", nil},
+		{"IBAN", de, func(cp *ExternalCounterparty) {}, "", nil},
+		{"US", us, func(cp *ExternalCounterparty) {}, "", nil},
+		{"individual", gb, func(cp *ExternalCounterparty) { cp.Company, cp.Name = "", &IndividualName{Last: "Lovelace"} }, "", nil},
+		{"no name", gb, func(cp *ExternalCounterparty) { cp.Company = "" }, "company_name", ErrMissingName},
+		{"empty individual name", gb, func(cp *ExternalCounterparty) { cp.Company, cp.Name = "", &IndividualName{} }, "company_name", ErrMissingName},
+		{"bad country", gb, func(cp *ExternalCounterparty) { cp.BankCountry = "UK" }, "bank_country", ErrCountryCode},
+		{"bad currency", gb, func(cp *ExternalCounterparty) { cp.Currency = "POUND" }, "currency", ErrCurrencyCode},
+		{"bad IBAN", de, func(cp *ExternalCounterparty) { cp.IBAN = "DE89370400440532013001" }, "iban", ErrIBANChecksum},
+		{"bad BIC", de, func(cp *ExternalCounterparty) { cp.BIC = "DEUT" }, "bic", ErrBICFormat},
+		{"bad routing number", us, func(cp *ExternalCounterparty) { cp.RoutingNo = "021000022" }, "routing_number", ErrRoutingNumber},
+		{"bad sort code", gb, func(cp *ExternalCounterparty) { cp.SortCode = "08-99-9A" }, "sort_code", ErrSortCode},
+		{"bad account number", gb, func(cp *ExternalCounterparty) { cp.AccountNo = "6637495" }, "account_no", ErrAccountNumber},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cp := tt.cp
+			tt.edit(&cp)
+			checkValidation(t, cp.Validate(), tt.field, tt.want)
+		})
+	}
+}
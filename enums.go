@@ -0,0 +1,270 @@
+package revolut
+
+import (
+	"encoding/json"
+)
+
+// TransactionState is the state of a payment, transfer, exchange or other transaction.
+type TransactionState string
+
+// Transaction states.
+const (
+	StateCreated   TransactionState = "created"
+	StatePending   TransactionState = "pending"
+	StateCompleted TransactionState = "completed"
+	StateDeclined  TransactionState = "declined"
+	StateFailed    TransactionState = "failed"
+	StateReverted  TransactionState = "reverted"
+)
+
+// String returns the state as sent by the API.
+func (s TransactionState) String() string {
+	return string(s)
+}
+
+// Known reports whether s is one of the documented states.
+func (s TransactionState) Known() bool {
+	switch s {
+	case StateCreated, StatePending, StateCompleted, StateDeclined, StateFailed, StateReverted:
+		return true
+	}
+	return false
+}
+
+// IsTerminal reports whether the transaction won't change state again, short of a reversal.
+func (s TransactionState) IsTerminal() bool {
+	switch s {
+	case StateCompleted, StateDeclined, StateFailed, StateReverted:
+		return true
+	}
+	return false
+}
+
+// IsFailure reports whether the transaction was declined, failed or reverted.
+func (s TransactionState) IsFailure() bool {
+	switch s {
+	case StateDeclined, StateFailed, StateReverted:
+		return true
+	}
+	return false
+}
+
+// MarshalJSON writes the state as a string.
+func (s TransactionState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(s))
+}
+
+// UnmarshalJSON reads a string or null. Unknown states are kept as they are.
+func (s *TransactionState) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, (*string)(s))
+}
+
+// TransactionType is the kind of transaction.
+type TransactionType string
+
+// Transaction types.
+const (
+	TypeATM            TransactionType = "atm"
+	TypeCardPayment    TransactionType = "card_payment"
+	TypeCardRefund     TransactionType = "card_refund"
+	TypeCardChargeback TransactionType = "card_chargeback"
+	TypeCardCredit     TransactionType = "card_credit"
+	TypeExchange       TransactionType = "exchange"
+	TypeTransfer       TransactionType = "transfer"
+	TypeLoan           TransactionType = "loan"
+	TypeFee            TransactionType = "fee"
+	TypeRefund         TransactionType = "refund"
+	TypeTopup          TransactionType = "topup"
+	TypeTopupReturn    TransactionType = "topup_return"
+	TypeTax            TransactionType = "tax"
+	TypeTaxRefund      TransactionType = "tax_refund"
+)
+
+// String returns the type as sent by the API.
+func (t TransactionType) String() string {
+	return string(t)
+}
+
+// Known reports whether t is one of the documented types.
+func (t TransactionType) Known() bool {
+	switch t {
+	case TypeATM, TypeCardPayment, TypeCardRefund, TypeCardChargeback, TypeCardCredit, TypeExchange, TypeTransfer,
+		TypeLoan, TypeFee, TypeRefund, TypeTopup, TypeTopupReturn, TypeTax, TypeTaxRefund:
+		return true
+	}
+	return false
+}
+
+// IsCard reports whether the transaction was made with a card.
+func (t TransactionType) IsCard() bool {
+	switch t {
+	case TypeCardPayment, TypeCardRefund, TypeCardChargeback, TypeCardCredit:
+		return true
+	}
+	return false
+}
+
+// MarshalJSON writes the type as a string.
+func (t TransactionType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(t))
+}
+
+// UnmarshalJSON reads a string or null. Unknown types are kept as they are.
+func (t *TransactionType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, (*string)(t))
+}
+
+// ReasonCode explains why a transaction was declined or failed. The API doesn't document
+// a fixed set, so it's a plain code to show or log.
+type ReasonCode string
+
+// String returns the code as sent by the API.
+func (r ReasonCode) String() string {
+	return string(r)
+}
+
+// MarshalJSON writes the code as a string.
+func (r ReasonCode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(r))
+}
+
+// UnmarshalJSON reads a string or null.
+func (r *ReasonCode) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, (*string)(r))
+}
+
+// ProfileType is the kind of Revolut profile a counterparty has.
+type ProfileType string
+
+// Profile types.
+const (
+	ProfilePersonal ProfileType = "personal"
+	ProfileBusiness ProfileType = "business"
+)
+
+// String returns the profile type as sent by the API.
+func (p ProfileType) String() string {
+	return string(p)
+}
+
+// Known reports whether p is one of the documented profile types.
+func (p ProfileType) Known() bool {
+	return p == ProfilePersonal || p == ProfileBusiness
+}
+
+// MarshalJSON writes the profile type as a string.
+func (p ProfileType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(p))
+}
+
+// UnmarshalJSON reads a string or null. Unknown profile types are kept as they are.
+func (p *ProfileType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, (*string)(p))
+}
+
+// AccountState is the state of one of your accounts.
+type AccountState string
+
+// Account states.
+const (
+	AccountActive   AccountState = "active"
+	AccountInactive AccountState = "inactive"
+)
+
+// String returns the state as sent by the API.
+func (s AccountState) String() string {
+	return string(s)
+}
+
+// Known reports whether s is one of the documented states.
+func (s AccountState) Known() bool {
+	return s == AccountActive || s == AccountInactive
+}
+
+// IsActive reports whether the account can be used.
+func (s AccountState) IsActive() bool {
+	return s == AccountActive
+}
+
+// MarshalJSON writes the state as a string.
+func (s AccountState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(s))
+}
+
+// UnmarshalJSON reads a string or null. Unknown states are kept as they are.
+func (s *AccountState) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, (*string)(s))
+}
+
+// CounterpartyState is the state of a counterparty.
+type CounterpartyState string
+
+// Counterparty states.
+const (
+	CounterpartyCreated CounterpartyState = "created"
+	CounterpartyDeleted CounterpartyState = "deleted"
+)
+
+// String returns the state as sent by the API.
+func (s CounterpartyState) String() string {
+	return string(s)
+}
+
+// Known reports whether s is one of the documented states.
+func (s CounterpartyState) Known() bool {
+	return s == CounterpartyCreated || s == CounterpartyDeleted
+}
+
+// MarshalJSON writes the state as a string.
+func (s CounterpartyState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(s))
+}
+
+// UnmarshalJSON reads a string or null. Unknown states are kept as they are.
+func (s *CounterpartyState) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, (*string)(s))
+}
+
+// CounterpartyType says where the other side of a transaction or counterparty account is.
+type CounterpartyType string
+
+// Counterparty types. Self is only used for the legs of transactions between your own accounts.
+const (
+	CounterpartySelf     CounterpartyType = "self"
+	CounterpartyRevolut  CounterpartyType = "revolut"
+	CounterpartyExternal CounterpartyType = "external"
+)
+
+// String returns the type as sent by the API.
+func (t CounterpartyType) String() string {
+	return string(t)
+}
+
+// Known reports whether t is one of the documented types.
+func (t CounterpartyType) Known() bool {
+	switch t {
+	case CounterpartySelf, CounterpartyRevolut, CounterpartyExternal:
+		return true
+	}
+	return false
+}
+
+// MarshalJSON writes the type as a string.
+func (t CounterpartyType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(t))
+}
+
+// UnmarshalJSON reads a string or null. Unknown types are kept as they are.
+func (t *CounterpartyType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, (*string)(t))
+}
+
+// unmarshalEnum decodes a JSON string into s, leaving it empty for null.
+func unmarshalEnum(data []byte, s *string) error {
+	if string(data) == "null" {
+		*s = ""
+		return nil
+	}
+
+	return json.Unmarshal(data, s)
+}
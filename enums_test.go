@@ -0,0 +1,83 @@
+package revolut
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEnumJSON(t *testing.T) {
+	var v struct {
+		State        TransactionState  `json:"state"`
+		Type         TransactionType   `json:"type"`
+		Reason       ReasonCode        `json:"reason_code"`
+		Profile      ProfileType       `json:"profile_type"`
+		Account      AccountState      `json:"account_state"`
+		Counterparty CounterpartyState `json:"counterparty_state"`
+		Leg          CounterpartyType  `json:"leg_type"`
+	}
+
+	tests := []struct {
+		name  string
+		in    string
+		want  string
+		known bool
+		ok    bool
+	}{
+		{"known", `{"state":"completed","type":"card_payment","reason_code":"insufficient_balance","profile_type":"business","account_state":"active","counterparty_state":"created","leg_type":"self"}`,
+			"completed card_payment insufficient_balance business active created self", true, true},
+		{"unknown", `{"state":"frozen","type":"crypto","reason_code":"new_code","profile_type":"charity","account_state":"closed","counterparty_state":"blocked","leg_type":"other"}`,
+			"frozen crypto new_code charity closed blocked other", false, true},
+		{"null", `{"state":null,"type":null,"reason_code":null,"profile_type":null,"account_state":null,"counterparty_state":null,"leg_type":null}`,
+			"      ", false, true},
+		{"number", `{"state":1}`, "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v.State, v.Type, v.Reason, v.Profile, v.Account, v.Counterparty, v.Leg = "x", "x", "x", "x", "x", "x", "x"
+			err := json.Unmarshal([]byte(tt.in), &v)
+			if (err == nil) != tt.ok {
+				t.Fatalf("error %v", err)
+			}
+			if !tt.ok {
+				return
+			}
+
+			got := v.State.String() + " " + v.Type.String() + " " + v.Reason.String() + " " + v.Profile.String() + " " +
+				v.Account.String() + " " + v.Counterparty.String() + " " + v.Leg.String()
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+
+			known := []bool{v.State.Known(), v.Type.Known(), v.Profile.Known(), v.Account.Known(), v.Counterparty.Known(), v.Leg.Known()}
+			for i, k := range known {
+				if k != tt.known {
+					t.Errorf("field %d: Known() = %v, want %v", i, k, tt.known)
+				}
+			}
+
+			out, err := json.Marshal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.name != "null" && string(out) != tt.in {
+				t.Errorf("round trip:\ngot  %s\nwant %s", out, tt.in)
+			}
+		})
+	}
+}
+
+func TestTransactionQueryTypes(t *testing.T) {
+	q := TransactionQuery{Types: []TransactionType{TypeFee, TypeCardPayment}}
+	if err := q.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got := q.Encode(); got != "type=fee&type=card_payment" {
+		t.Errorf("Encode() = %q", got)
+	}
+
+	q.Types = append(q.Types, "crypto")
+	if err := q.Validate(); err == nil {
+		t.Error("unknown type passed Validate()")
+	}
+}
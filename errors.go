@@ -1,5 +1,11 @@
 package revolut
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
 // Revolut errors
 const (
 	errBadRequest   = "bad request - check syntax"
@@ -19,27 +25,176 @@ const (
 	ErrKeyFormat = "API key has the wrong format - not starting with sand_ or prod_"
 )
 
-func codeToError(code int) string {
-	var msg = ""
+var (
+	// ErrEmptyResponse means a call expecting data got a successful response without a body.
+	ErrEmptyResponse = errors.New("empty response from API")
+	// ErrResponseTooLarge means the response body was larger than the client's MaxResponseSize.
+	ErrResponseTooLarge = errors.New("response from API is too large")
+	// ErrExchangeAmount means an exchange request needs an amount on exactly one side.
+	ErrExchangeAmount = errors.New("exchange amount must be set on either the source or the target, not both")
+)
+
+// Validation errors, wrapped with details by the Validate functions. Compare with errors.Is().
+var (
+	// ErrCountryCode means a country isn't an ISO 3166-1 alpha-2 code.
+	ErrCountryCode = errors.New("invalid country code")
+	// ErrCurrencyCode means a currency isn't an ISO 4217 code.
+	ErrCurrencyCode = errors.New("invalid currency code")
+	// ErrIBANFormat means an IBAN has the wrong characters or an unknown country.
+	ErrIBANFormat = errors.New("invalid IBAN")
+	// ErrIBANLength means an IBAN has the wrong length for its country.
+	ErrIBANLength = errors.New("wrong IBAN length")
+	// ErrIBANChecksum means the check digits of an IBAN don't match.
+	ErrIBANChecksum = errors.New("wrong IBAN check digits")
+	// ErrBICFormat means a BIC doesn't have the structure of a SWIFT code.
+	ErrBICFormat = errors.New("invalid BIC")
+	// ErrRoutingNumber means a US ABA routing number is malformed or has a bad checksum.
+	ErrRoutingNumber = errors.New("invalid routing number")
+	// ErrSortCode means a UK sort code is malformed.
+	ErrSortCode = errors.New("invalid sort code")
+	// ErrAccountNumber means an account number is malformed.
+	ErrAccountNumber = errors.New("invalid account number")
+	// ErrModulusCheck means a UK account number fails the modulus check for its sort code.
+	ErrModulusCheck = errors.New("account number doesn't match sort code")
+	// ErrMissingName means a counterparty has neither a company nor an individual name.
+	ErrMissingName = errors.New("company or individual name required")
+	// ErrFieldRequired means a field needed for the kind of account is empty.
+	ErrFieldRequired = errors.New("required")
+	// ErrFieldNotAllowed means a field is set which the kind of account doesn't use.
+	ErrFieldNotAllowed = errors.New("not allowed")
+)
+
+// Pre-flight errors, found by PaymentRequest.Validate() and TransferRequest.Validate().
+var (
+	// ErrAmountNotPositive means a payment or transfer is for zero or less.
+	ErrAmountNotPositive = errors.New("amount must be more than zero")
+	// ErrUnknownAccount means an account ID doesn't match any account.
+	ErrUnknownAccount = errors.New("no such account")
+	// ErrAccountInactive means an account can't be used.
+	ErrAccountInactive = errors.New("account is not active")
+	// ErrCurrencyMismatch means an account doesn't hold or take the currency.
+	ErrCurrencyMismatch = errors.New("wrong currency")
+	// ErrInsufficientFunds means the balance doesn't cover the amount.
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrUnknownCounterparty means a counterparty ID doesn't match a current counterparty.
+	ErrUnknownCounterparty = errors.New("no such counterparty")
+	// ErrSameAccount means a transfer has the same source and target.
+	ErrSameAccount = errors.New("source and target are the same account")
+)
+
+// Sentinel errors for the HTTP status codes the API is documented to return.
+// Compare with errors.Is() on any error returned by a Client method.
+var (
+	// ErrBadRequest is returned for status 400.
+	ErrBadRequest = errors.New(errBadRequest)
+	// ErrUnauthorized is returned for status 401.
+	ErrUnauthorized = errors.New(errUnauthorized)
+	// ErrForbidden is returned for status 403.
+	ErrForbidden = errors.New(errForbidden)
+	// ErrNotFound is returned for status 404.
+	ErrNotFound = errors.New(errNotFound)
+	// ErrMethodNotAllowed is returned for status 405.
+	ErrMethodNotAllowed = errors.New(errDisallowed)
+	// ErrNotAcceptable is returned for status 406.
+	ErrNotAcceptable = errors.New(errUnacceptable)
+	// ErrRateLimited is returned for status 429.
+	ErrRateLimited = errors.New(errHammer)
+	// ErrInternal is returned for status 500 and unknown 5xx codes.
+	ErrInternal = errors.New(errInternal)
+	// ErrUnavailable is returned for status 501 and 503.
+	ErrUnavailable = errors.New(errUnavailable)
+)
+
+// APIError is returned by Client methods when the API responds with anything but success.
+type APIError struct {
+	// Status is the HTTP status code.
+	Status int
+	// Code is Revolut's internal error code, if the response had one.
+	Code int
+	// Message is the explanation from the API, or a generic description of the status code.
+	Message string
+	// Method is the HTTP method of the failed request.
+	Method string
+	// Endpoint is the API path of the failed request, without the base URL.
+	Endpoint string
+	// RequestID is the client-supplied request ID for payments and transfers.
+	RequestID string
+	// CorrelationID is the value sent in the CorrelationHeader.
+	CorrelationID string
+}
+
+// Error formats the method, endpoint, status and message.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s %s: %d %s", e.Method, e.Endpoint, e.Status, e.Message)
+}
+
+// Is lets errors.Is() match an APIError against the sentinel for its status code.
+func (e *APIError) Is(target error) bool {
+	err := statusError(e.Status)
+	return err != nil && err == target
+}
+
+// newAPIError builds an APIError from a failed response, using the JSON error body if there is one.
+func newAPIError(method, endpoint, cid string, status int, body []byte) *APIError {
+	e := &APIError{
+		Status:        status,
+		Method:        method,
+		Endpoint:      endpoint,
+		CorrelationID: cid,
+	}
+
+	var resp ErrorResponse
+	if json.Unmarshal(body, &resp) == nil {
+		e.Code = resp.Code
+		e.Message = resp.Message
+	}
+
+	if e.Message == "" {
+		e.Message = codeToError(status)
+	}
+	return e
+}
+
+// withRequestID tags an APIError with the client-supplied request ID.
+func withRequestID(err error, id string) error {
+	var e *APIError
+	if errors.As(err, &e) {
+		e.RequestID = id
+	}
+	return err
+}
+
+// statusError returns the sentinel error for a HTTP status code, or nil if there is none.
+func statusError(code int) error {
 	switch code {
 	case 400:
-		msg = errBadRequest
+		return ErrBadRequest
 	case 401:
-		msg = errUnauthorized
+		return ErrUnauthorized
 	case 403:
-		msg = errForbidden
+		return ErrForbidden
 	case 404:
-		msg = errNotFound
+		return ErrNotFound
 	case 405:
-		msg = errDisallowed
+		return ErrMethodNotAllowed
 	case 406:
-		msg = errUnacceptable
+		return ErrNotAcceptable
 	case 429:
-		msg = errHammer
-	case 500:
-		msg = errInternal
-	case 501:
-		msg = errUnavailable
+		return ErrRateLimited
+	case 501, 503:
+		return ErrUnavailable
+	}
+
+	if code >= 500 && code < 600 {
+		return ErrInternal
+	}
+	return nil
+}
+
+func codeToError(code int) string {
+	if err := statusError(code); err != nil {
+		return err.Error()
 	}
-	return msg
+
+	return fmt.Sprintf("unexpected HTTP status %d", code)
 }
@@ -0,0 +1,158 @@
+package revolut
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// ExchangeAmount is an amount in a currency, as used in rate quotes.
+type ExchangeAmount struct {
+	// Amount of money.
+	Amount Amount `json:"amount"`
+	// Currency is a 3-letter ISO code.
+	Currency string `json:"currency"`
+}
+
+// Money converts to the general Money type.
+func (e ExchangeAmount) Money() Money {
+	return Money{Amount: e.Amount, Currency: e.Currency}
+}
+
+// ExchangeRate is a quote for exchanging between two currencies.
+type ExchangeRate struct {
+	// From is the amount to sell.
+	From ExchangeAmount `json:"from"`
+	// To is the amount it would buy.
+	To ExchangeAmount `json:"to"`
+	// Rate is the exchange rate applied.
+	Rate Rate `json:"rate"`
+	// Fee charged for the exchange.
+	Fee ExchangeAmount `json:"fee"`
+	// RateDate is when the rate was set.
+	RateDate Time `json:"rate_date"`
+}
+
+// ExchangeSide is one of the accounts in an exchange.
+type ExchangeSide struct {
+	// AccountID to take from or put the money in.
+	AccountID string `json:"account_id"`
+	// Currency of the account.
+	Currency string `json:"currency"`
+	// Amount must be set on exactly one side: the source to sell a fixed amount,
+	// or the target to buy a fixed amount.
+	Amount *Amount `json:"amount,omitempty"`
+}
+
+// ExchangeRequest moves money between two of your accounts in different currencies.
+type ExchangeRequest struct {
+	// From is the account to sell from.
+	From ExchangeSide `json:"from"`
+	// To is the account to buy into.
+	To ExchangeSide `json:"to"`
+	// Reference is an optional text to show on the transaction.
+	Reference string `json:"reference,omitempty"`
+	// RequestID for the exchange, provided by the client.
+	RequestID string `json:"request_id"`
+}
+
+func (r ExchangeRequest) requestID() string {
+	return r.RequestID
+}
+
+// ExchangeResponse to an exchange request.
+type ExchangeResponse struct {
+	// ID of the created transaction.
+	ID string `json:"id"`
+	// Type is "exchange".
+	Type TransactionType `json:"type"`
+	// State is one of "pending", "completed", "declined" or "failed".
+	State TransactionState `json:"state"`
+	// Reason is a code for the "declined" or "failed" states.
+	Reason ReasonCode `json:"reason_code,omitempty"`
+	// CreatedAt is the ISO time when the exchange was requested.
+	CreatedAt Time `json:"created_at"`
+	// CompletedAt is the ISO time when the exchange finished.
+	CompletedAt Time `json:"completed_at"`
+	// Rate applied, if the API reports it.
+	Rate Rate `json:"rate"`
+	// Fee charged, if the API reports it.
+	Fee *ExchangeAmount `json:"fee,omitempty"`
+}
+
+// GetRate gets a quote for selling amount of one currency for another.
+func (c *Client) GetRate(from, to string, amount Amount) (*ExchangeRate, error) {
+	return c.GetRateContext(context.Background(), from, to, amount)
+}
+
+// GetRateContext is GetRate with a context for deadlines and cancellation.
+func (c *Client) GetRateContext(ctx context.Context, from, to string, amount Amount) (*ExchangeRate, error) {
+	v := url.Values{}
+	v.Set("from", strings.ToUpper(from))
+	v.Set("to", strings.ToUpper(to))
+	if !amount.IsZero() {
+		v.Set("amount", amount.String())
+	}
+
+	var rate ExchangeRate
+	err := c.do(ctx, "GET", epRate+"?"+v.Encode(), nil, &rate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rate, nil
+}
+
+// Exchange money between two of your accounts. A non-empty request ID makes the exchange
+// idempotent, so it will be retried on transient failures. An empty one is made by the client's
+// RequestIDs generator, if it has one.
+func (c *Client) Exchange(req ExchangeRequest) (*ExchangeResponse, error) {
+	return c.ExchangeContext(context.Background(), req)
+}
+
+// ExchangeContext is Exchange with a context for deadlines and cancellation.
+func (c *Client) ExchangeContext(ctx context.Context, req ExchangeRequest) (*ExchangeResponse, error) {
+	req.From.Currency = strings.ToUpper(req.From.Currency)
+	req.To.Currency = strings.ToUpper(req.To.Currency)
+	if (req.From.Amount == nil) == (req.To.Amount == nil) {
+		return nil, ErrExchangeAmount
+	}
+
+	for _, side := range []ExchangeSide{req.From, req.To} {
+		if side.Amount == nil {
+			continue
+		}
+
+		_, err := side.Amount.Minor(CurrencyExponent(side.Currency))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	d := RequestDetails{
+		Account:      req.From.AccountID,
+		Counterparty: req.To.AccountID,
+		Currency:     req.From.Currency,
+		Reference:    req.Reference,
+	}
+	if req.From.Amount != nil {
+		d.Amount = *req.From.Amount
+	} else {
+		d.Amount = *req.To.Amount
+		d.Currency = req.To.Currency
+	}
+
+	var err error
+	req.RequestID, err = c.requestID(req.RequestID, d)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ExchangeResponse
+	err = c.do(ctx, "POST", epExchange, req, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
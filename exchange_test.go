@@ -0,0 +1,81 @@
+package revolut_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Urethramancer/revolut"
+	"github.com/Urethramancer/revolut/revoluttest"
+)
+
+func TestGetRate(t *testing.T) {
+	srv := revoluttest.NewServer()
+	defer srv.Close()
+	srv.SetRate("GBP", "JPY", revolut.NewRate(15055, 2))
+	c, err := srv.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rate, err := c.GetRate("gbp", "jpy", revolut.NewAmount(1000, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rate.Rate.String() != "150.55" || rate.To.Money().String() != "1506 JPY" {
+		t.Errorf("got rate %s for %s", rate.Rate, rate.To.Money())
+	}
+
+	_, err = c.GetRate("GBP", "USD", revolut.NewAmount(1, 0))
+	var ae *revolut.APIError
+	if !errors.As(err, &ae) {
+		t.Errorf("unknown pair: got %v, want an APIError", err)
+	}
+}
+
+func TestExchange(t *testing.T) {
+	ten := revolut.NewAmount(1000, 2)
+	yen := revolut.NewAmount(3011, 0)
+	tests := []struct {
+		name     string
+		sell     *revolut.Amount
+		buy      *revolut.Amount
+		err      error
+		gbp, jpy string
+	}{
+		{"sell", &ten, nil, nil, "90.00", "1506"},
+		{"buy", nil, &yen, nil, "80.00", "3011"},
+		{"both amounts", &ten, &yen, revolut.ErrExchangeAmount, "100.00", "0"},
+		{"no amount", nil, nil, revolut.ErrExchangeAmount, "100.00", "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := revoluttest.NewServer()
+			defer srv.Close()
+			gbp := srv.AddAccount(revolut.Account{Name: "GBP", Currency: "GBP", Balance: revolut.NewAmount(10000, 2)})
+			jpy := srv.AddAccount(revolut.Account{Name: "JPY", Currency: "JPY"})
+			srv.SetRate("GBP", "JPY", revolut.NewRate(15055, 2))
+			c, err := srv.NewClient()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = c.Exchange(revolut.ExchangeRequest{
+				From:      revolut.ExchangeSide{AccountID: gbp.ID, Currency: "gbp", Amount: tt.sell},
+				To:        revolut.ExchangeSide{AccountID: jpy.ID, Currency: "jpy", Amount: tt.buy},
+				RequestID: "fx-1",
+			})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got %v, want %v", err, tt.err)
+			}
+
+			for _, want := range []struct{ id, balance string }{{gbp.ID, tt.gbp}, {jpy.ID, tt.jpy}} {
+				acc, _ := srv.Account(want.id)
+				if got := acc.Balance.Format(revolut.CurrencyExponent(acc.Currency)); got != want.balance {
+					t.Errorf("%s balance %s, want %s", acc.Currency, got, want.balance)
+				}
+			}
+		})
+	}
+}
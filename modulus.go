@@ -0,0 +1,163 @@
+package revolut
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Modulus checking methods used in the Vocalink table.
+const (
+	ModulusMOD10 = "MOD10"
+	ModulusMOD11 = "MOD11"
+	ModulusDBLAL = "DBLAL"
+)
+
+// ModulusRule is one row of the Vocalink modulus weight table, covering a range of sort codes.
+type ModulusRule struct {
+	// From is the first sort code in the range.
+	From int
+	// To is the last sort code in the range.
+	To int
+	// Method is ModulusMOD10, ModulusMOD11 or ModulusDBLAL.
+	Method string
+	// Weights for the six sort code digits followed by the eight account number digits.
+	Weights [14]int
+	// Exception is the exception code, or 0 for none.
+	Exception int
+}
+
+// check runs the rule on the 14 digits of a sort code and account number.
+func (r ModulusRule) check(digits string) bool {
+	sum := 0
+	for i := 0; i < 14; i++ {
+		n := int(digits[i]-'0') * r.Weights[i]
+		if r.Method == ModulusDBLAL {
+			// Add the digits of each product, not the product itself.
+			n = n/10 + n%10
+		}
+		sum += n
+	}
+
+	if r.Method == ModulusMOD11 {
+		return sum%11 == 0
+	}
+
+	return sum%10 == 0
+}
+
+// ModulusTable holds the rules for UK account number modulus checking, in the order of the source file.
+type ModulusTable []ModulusRule
+
+// UKModulusTable is used by ValidateUKAccount. It's empty by default, so only the format is checked.
+// Load the current valacdos.txt from Vocalink with LoadUKModulusTable to enable it.
+var UKModulusTable ModulusTable
+
+// LoadUKModulusTable reads a Vocalink modulus weight table file into UKModulusTable.
+// The table is left as it was if the file can't be read.
+func LoadUKModulusTable(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+	table, err := ParseModulusTable(f)
+	if err != nil {
+		return err
+	}
+
+	UKModulusTable = table
+	return nil
+}
+
+// ParseModulusTable reads the Vocalink modulus weight table format: a sort code range, a method,
+// 14 weights and an optional exception code per line, separated by whitespace.
+func ParseModulusTable(r io.Reader) (ModulusTable, error) {
+	var table ModulusTable
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		if len(fields) != 17 && len(fields) != 18 {
+			return nil, fmt.Errorf("modulus table line %d: expected 17 or 18 fields, got %d", line, len(fields))
+		}
+
+		var rule ModulusRule
+		var err error
+		rule.From, err = strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("modulus table line %d: %w", line, err)
+		}
+
+		rule.To, err = strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("modulus table line %d: %w", line, err)
+		}
+
+		rule.Method = fields[2]
+		switch rule.Method {
+		case ModulusMOD10, ModulusMOD11, ModulusDBLAL:
+		default:
+			return nil, fmt.Errorf("modulus table line %d: unknown method %q", line, rule.Method)
+		}
+
+		for i := range rule.Weights {
+			rule.Weights[i], err = strconv.Atoi(fields[3+i])
+			if err != nil {
+				return nil, fmt.Errorf("modulus table line %d: %w", line, err)
+			}
+		}
+
+		if len(fields) == 18 {
+			rule.Exception, err = strconv.Atoi(fields[17])
+			if err != nil {
+				return nil, fmt.Errorf("modulus table line %d: %w", line, err)
+			}
+		}
+
+		table = append(table, rule)
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// Check runs every rule covering the sort code against the account number. Sort codes without
+// rules can't be checked and pass. Rules with an exception code are skipped, since the exceptions
+// change the calculation in bank-specific ways this package doesn't implement.
+func (t ModulusTable) Check(sortCode, accountNo string) error {
+	if len(sortCode) != 6 || !isDigits(sortCode) {
+		return fmt.Errorf("%w: %q must have 6 digits", ErrSortCode, sortCode)
+	}
+
+	if len(accountNo) != 8 || !isDigits(accountNo) {
+		return fmt.Errorf("%w: %q must have 8 digits", ErrAccountNumber, accountNo)
+	}
+
+	sc, _ := strconv.Atoi(sortCode)
+	digits := sortCode + accountNo
+	for _, rule := range t {
+		if sc < rule.From || sc > rule.To || rule.Exception != 0 {
+			continue
+		}
+
+		if !rule.check(digits) {
+			return fmt.Errorf("%w: %s %s fails %s", ErrModulusCheck, sortCode, accountNo, rule.Method)
+		}
+	}
+
+	return nil
+}
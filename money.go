@@ -0,0 +1,326 @@
+package revolut
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Amount is an exact decimal quantity of money. It is stored as an integer
+// and a number of decimal places, and goes to and from JSON numbers without
+// passing through floating point.
+type Amount struct {
+	// value is the unscaled integer.
+	value int64
+	// scale is the number of digits after the decimal point.
+	scale int
+}
+
+// maxDigits is the most significant digits ParseAmount accepts. An int64 holds 18, which leaves
+// room for the up to three minor unit decimals of any currency to be added without overflowing.
+const maxDigits = 15
+
+var (
+	// ErrAmountFormat means a string isn't a plain decimal number.
+	ErrAmountFormat = errors.New("amount must be a decimal number like 12 or 12.34")
+	// ErrAmountPrecision means an amount has more decimals than its currency allows.
+	ErrAmountPrecision = errors.New("amount has more decimal places than the currency allows")
+	// ErrAmountOverflow means a result is too large to hold exactly.
+	ErrAmountOverflow = errors.New("amount is too large")
+)
+
+// NewAmount creates an Amount from integer minor units, with exp decimal places.
+// NewAmount(1234, 2) is 12.34.
+func NewAmount(minor int64, exp int) Amount {
+	return Amount{value: minor, scale: exp}
+}
+
+// ParseAmount reads a decimal string such as "-12.34". Exponents aren't accepted.
+func ParseAmount(s string) (Amount, error) {
+	var a Amount
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	parts := strings.SplitN(s, ".", 2)
+	digits := parts[0]
+	if len(parts) == 2 {
+		digits += parts[1]
+		a.scale = len(parts[1])
+	}
+
+	if digits == "" || strings.TrimLeft(digits, "0123456789") != "" {
+		return Amount{}, ErrAmountFormat
+	}
+
+	digits = strings.TrimLeft(digits, "0")
+	if len(digits) > maxDigits {
+		return Amount{}, fmt.Errorf("amount %s has too many digits", s)
+	}
+
+	if digits != "" {
+		n, err := strconv.ParseInt(digits, 10, 64)
+		if err != nil {
+			return Amount{}, err
+		}
+		a.value = n
+	}
+
+	if neg {
+		a.value = -a.value
+	}
+	return a, nil
+}
+
+// String formats the amount with as many decimals as it has.
+func (a Amount) String() string {
+	return a.Format(0)
+}
+
+// Format returns the amount with at least exp decimals, padding with zeros. Use the
+// currency's exponent to get the conventional form, such as "12.50" rather than "12.5".
+func (a Amount) Format(exp int) string {
+	if a.scale < exp {
+		b, ok := a.rescale(exp)
+		if !ok {
+			return a.Format(0)
+		}
+		a = b
+	}
+
+	v := a.value
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+
+	s := strconv.FormatInt(v, 10)
+	if a.scale == 0 {
+		return sign + s
+	}
+
+	if len(s) <= a.scale {
+		s = strings.Repeat("0", a.scale-len(s)+1) + s
+	}
+	return sign + s[:len(s)-a.scale] + "." + s[len(s)-a.scale:]
+}
+
+// MarshalJSON writes the amount as an exact JSON number.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON reads a JSON number, or a string holding one, without rounding.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" || s == "" {
+		*a = Amount{}
+		return nil
+	}
+
+	// Accept exponent notation in case the API ever sends it.
+	if strings.ContainsAny(s, "eE") {
+		x, ok := new(big.Rat).SetString(s)
+		if !ok {
+			return ErrAmountFormat
+		}
+
+		d := x.FloatString(decimals(x))
+		y, _ := new(big.Rat).SetString(d)
+		if y.Cmp(x) != 0 {
+			return fmt.Errorf("amount %s has too many digits", s)
+		}
+		s = d
+	}
+
+	x, err := ParseAmount(s)
+	if err != nil {
+		return err
+	}
+
+	*a = x
+	return nil
+}
+
+// Minor returns the amount in integer minor units of a currency with exp decimals.
+// It fails with ErrAmountPrecision if that would lose digits.
+func (a Amount) Minor(exp int) (int64, error) {
+	a = a.trim()
+	if a.scale > exp {
+		return 0, ErrAmountPrecision
+	}
+
+	a, ok := a.rescale(exp)
+	if !ok {
+		return 0, ErrAmountOverflow
+	}
+	return a.value, nil
+}
+
+// Float64 is an approximation for display and statistics only.
+func (a Amount) Float64() float64 {
+	f, _ := strconv.ParseFloat(a.String(), 64)
+	return f
+}
+
+// Sign returns -1, 0 or 1.
+func (a Amount) Sign() int {
+	switch {
+	case a.value < 0:
+		return -1
+	case a.value > 0:
+		return 1
+	}
+	return 0
+}
+
+// IsZero is true for any zero amount.
+func (a Amount) IsZero() bool {
+	return a.value == 0
+}
+
+// Neg returns -a. The most negative unscaled value has no positive counterpart in an int64,
+// so it saturates to the largest one instead of overflowing. Use Sub for a checked negation.
+func (a Amount) Neg() Amount {
+	if a.value == math.MinInt64 {
+		a.value = math.MaxInt64
+		return a
+	}
+
+	a.value = -a.value
+	return a
+}
+
+// Add returns a+b. It fails with ErrAmountOverflow if the sum doesn't fit.
+func (a Amount) Add(b Amount) (Amount, error) {
+	a, b, ok := align(a, b)
+	if !ok {
+		return Amount{}, ErrAmountOverflow
+	}
+
+	sum := a.value + b.value
+	if (sum > a.value) != (b.value > 0) {
+		return Amount{}, ErrAmountOverflow
+	}
+
+	a.value = sum
+	return a, nil
+}
+
+// Sub returns a-b. It fails with ErrAmountOverflow if the difference doesn't fit.
+func (a Amount) Sub(b Amount) (Amount, error) {
+	a, b, ok := align(a, b)
+	if !ok {
+		return Amount{}, ErrAmountOverflow
+	}
+
+	diff := a.value - b.value
+	if (a.value >= 0) != (b.value >= 0) && (diff >= 0) != (a.value >= 0) {
+		return Amount{}, ErrAmountOverflow
+	}
+
+	a.value = diff
+	return a, nil
+}
+
+// Cmp returns -1 if a < b, 0 if they are equal and 1 if a > b. It's exact for any two amounts.
+func (a Amount) Cmp(b Amount) int {
+	scale := a.scale
+	if b.scale > scale {
+		scale = b.scale
+	}
+	return a.big(scale).Cmp(b.big(scale))
+}
+
+// big returns the unscaled value of a at a larger scale, without overflowing.
+func (a Amount) big(scale int) *big.Int {
+	x := big.NewInt(a.value)
+	exp := big.NewInt(int64(scale - a.scale))
+	return x.Mul(x, exp.Exp(big.NewInt(10), exp, nil))
+}
+
+// rescale changes the number of decimals. Only use it to add decimals.
+// It reports false if the value would overflow.
+func (a Amount) rescale(exp int) (Amount, bool) {
+	for a.scale < exp {
+		if a.value > math.MaxInt64/10 || a.value < math.MinInt64/10 {
+			return a, false
+		}
+		a.value *= 10
+		a.scale++
+	}
+	return a, true
+}
+
+// trim removes trailing zero decimals.
+func (a Amount) trim() Amount {
+	for a.scale > 0 && a.value%10 == 0 {
+		a.value /= 10
+		a.scale--
+	}
+	return a
+}
+
+// align gives two amounts the same scale. It reports false if either would overflow.
+func align(a, b Amount) (Amount, Amount, bool) {
+	var ok bool
+	if a.scale < b.scale {
+		a, ok = a.rescale(b.scale)
+	} else {
+		b, ok = b.rescale(a.scale)
+	}
+	return a, b, ok
+}
+
+// CurrencyExponent returns the number of minor unit decimals for an ISO 4217 currency code.
+// Unknown codes get the common 2.
+func CurrencyExponent(currency string) int {
+	switch strings.ToUpper(currency) {
+	case "BIF", "CLP", "DJF", "GNF", "ISK", "JPY", "KMF", "KRW", "PYG", "RWF", "UGX", "UYI", "VND", "VUV", "XAF", "XOF", "XPF":
+		return 0
+	case "BHD", "IQD", "JOD", "KWD", "LYD", "OMR", "TND":
+		return 3
+	}
+	return 2
+}
+
+// Money is an amount in a specific currency.
+type Money struct {
+	// Amount of money.
+	Amount Amount
+	// Currency is a 3-letter ISO code.
+	Currency string
+}
+
+// NewMoney creates Money from integer minor units of a currency. NewMoney(1234, "JPY") is ¥1234,
+// while NewMoney(1234, "GBP") is £12.34.
+func NewMoney(minor int64, currency string) Money {
+	currency = strings.ToUpper(currency)
+	return Money{Amount: NewAmount(minor, CurrencyExponent(currency)), Currency: currency}
+}
+
+// ParseMoney reads a decimal string in a currency, rejecting more decimals than the currency has.
+func ParseMoney(s, currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+	a, err := ParseAmount(s)
+	if err != nil {
+		return Money{}, err
+	}
+
+	m := Money{Amount: a, Currency: currency}
+	_, err = m.Minor()
+	return m, err
+}
+
+// Minor returns the amount in integer minor units of the currency.
+func (m Money) Minor() (int64, error) {
+	return m.Amount.Minor(CurrencyExponent(m.Currency))
+}
+
+// String formats the money as "12.34 GBP".
+func (m Money) String() string {
+	return m.Amount.Format(CurrencyExponent(m.Currency)) + " " + m.Currency
+}
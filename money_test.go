@@ -0,0 +1,121 @@
+package revolut
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseAmountDigits(t *testing.T) {
+	tests := []struct {
+		in string
+		ok bool
+	}{
+		{"999999999999999", true},
+		{"9999999999999.99", true},
+		{"9999999999999999", false},
+		{"99999999999999999", false},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseAmount(tt.in)
+		if (err == nil) != tt.ok {
+			t.Errorf("ParseAmount(%q) = %v", tt.in, err)
+		}
+	}
+}
+
+func TestAmountOverflow(t *testing.T) {
+	large := NewAmount(math.MaxInt64/2, 0)
+	_, err := large.Minor(2)
+	if !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("Minor: got %v, want ErrAmountOverflow", err)
+	}
+
+	_, err = NewAmount(math.MaxInt64, 0).Add(NewAmount(1, 0))
+	if !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("Add: got %v, want ErrAmountOverflow", err)
+	}
+
+	_, err = large.Neg().Sub(NewAmount(math.MaxInt64, 0))
+	if !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("Sub: got %v, want ErrAmountOverflow", err)
+	}
+
+	_, err = large.Add(NewAmount(1, 2))
+	if !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("Add with rescale: got %v, want ErrAmountOverflow", err)
+	}
+
+	_, err = NewAmount(0, 0).Sub(NewAmount(math.MinInt64, 0))
+	if !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("Sub of the smallest value: got %v, want ErrAmountOverflow", err)
+	}
+
+	diff, err := NewAmount(-1, 0).Sub(NewAmount(math.MinInt64, 0))
+	if err != nil || diff.Cmp(NewAmount(math.MaxInt64, 0)) != 0 {
+		t.Errorf("Sub: got %s, %v, want %d", diff, err, int64(math.MaxInt64))
+	}
+
+	if neg := NewAmount(math.MinInt64, 2).Neg(); neg.Cmp(NewAmount(math.MaxInt64, 2)) != 0 {
+		t.Errorf("Neg of the smallest value: got %s, want it saturated", neg)
+	}
+
+	sum, err := NewAmount(1250, 2).Add(NewAmount(5, 1))
+	if err != nil || sum.Cmp(NewAmount(13, 0)) != 0 {
+		t.Errorf("Add: got %s, %v, want 13", sum, err)
+	}
+}
+
+func TestAmountCmp(t *testing.T) {
+	tests := []struct {
+		a, b Amount
+		want int
+	}{
+		{NewAmount(1250, 2), NewAmount(125, 1), 0},
+		{NewAmount(1, 2), NewAmount(2, 2), -1},
+		{NewAmount(math.MaxInt64, 0), NewAmount(1, 2), 1},
+		{NewAmount(math.MinInt64, 0), NewAmount(math.MaxInt64, 3), -1},
+		{NewAmount(math.MaxInt64, 0), NewAmount(math.MinInt64, 0), 1},
+	}
+
+	for _, tt := range tests {
+		if got := tt.a.Cmp(tt.b); got != tt.want {
+			t.Errorf("%s.Cmp(%s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{`12.34`, "12.34", true},
+		{`"-0.5"`, "-0.5", true},
+		{`null`, "0", true},
+		{`1.5e2`, "150", true},
+		{`1234E-2`, "12.34", true},
+		{`"0.1e1"`, "1", true},
+		{`1.00000000000001e2`, "100.000000000001", true},
+		{`123456789012345e-3`, "123456789012.345", true},
+		{`1e-30`, "", false},
+		{`1e20`, "", false},
+		{`1e`, "", false},
+		{`"abc"`, "", false},
+	}
+
+	for _, tt := range tests {
+		var a Amount
+		err := json.Unmarshal([]byte(tt.in), &a)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: error %v", tt.in, err)
+			continue
+		}
+		if tt.ok && a.String() != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.in, a, tt.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package revolut
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Option configures a Client in NewClient.
+type Option func(*Client)
+
+// Middleware wraps the transport of a client. It sees every outgoing request
+// and its response, which makes it the place for logging, metrics, tracing and signing.
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts an ordinary function to http.RoundTripper, for use in middleware.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// WithHTTPClient replaces the default HTTP client settings, including timeout and transport.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.Client = *hc
+	}
+}
+
+// WithMiddleware adds middleware to the transport. The first one given is the outermost,
+// seeing requests first and responses last.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// WithBaseURL points the client at a different API root, such as a proxy or a fake server in tests.
+func WithBaseURL(url string) Option {
+	return func(c *Client) {
+		if !strings.HasSuffix(url, "/") {
+			url += "/"
+		}
+		c.baseURL = url
+	}
+}
+
+// WithUserAgent sets the User-Agent for the application.
+func WithUserAgent(agent string) Option {
+	return func(c *Client) {
+		c.Agent = agent
+	}
+}
+
+// WithHeader adds a default header to every request.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		if c.Header == nil {
+			c.Header = http.Header{}
+		}
+		c.Header.Add(key, value)
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.Retry = p
+	}
+}
+
+// WithRateLimiter throttles all requests through l.
+func WithRateLimiter(l *RateLimiter) Option {
+	return func(c *Client) {
+		c.Limiter = l
+	}
+}
+
+// WithRequestIDs generates request IDs with g when a payment, transfer or exchange has none.
+func WithRequestIDs(g RequestIDGenerator) Option {
+	return func(c *Client) {
+		c.RequestIDs = g
+	}
+}
+
+// chain wraps rt in the middleware, keeping the first one outermost.
+func chain(rt http.RoundTripper, mw []Middleware) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	for i := len(mw) - 1; i >= 0; i-- {
+		rt = mw[i](rt)
+	}
+	return rt
+}
@@ -1,7 +1,7 @@
 package revolut
 
 import (
-	"encoding/json"
+	"context"
 	"strings"
 )
 
@@ -14,7 +14,7 @@ type PaymentRequest struct {
 	// Receiver of this payment.
 	Receiver Receiver `json:"receiver"`
 	// Amount to pay.
-	Amount float64 `json:"amount"`
+	Amount Amount `json:"amount"`
 	// Currency for the transaction. 3-letter ISO code.
 	Currency string `json:"currency"`
 	// Reference is an optional text to show on the transaction. Highly recommended.
@@ -36,13 +36,17 @@ type PaymentResponse struct {
 	// ID of the created transaction.
 	ID string `json:"id"`
 	// State is one of "pending", "completed", "declined" or "failed".
-	State string `json:"state"`
+	State TransactionState `json:"state"`
 	// Reason is a code for the "declined" or "failed" states.
-	Reason string `json:"reason_code"`
+	Reason ReasonCode `json:"reason_code"`
 	// CreatedAt is the ISO time when the payment was requested.
-	CreatedAt string `json:"created_at"`
+	CreatedAt Time `json:"created_at"`
 	// CompletedAt is the ISO time when the payment finished. Not available for asynchronous or scheduled payments.
-	CompletedAt string `json:"completed_at"`
+	CompletedAt Time `json:"completed_at"`
+}
+
+func (r PaymentRequest) requestID() string {
+	return r.RequestID
 }
 
 // Card is used for card payments.
@@ -58,40 +62,57 @@ type Card struct {
 }
 
 // Pay a Revolut account or external account.
-func (c *Client) Pay(id, account, cp, cpAccount, currency, reference, schedule string, amount float64) (*PaymentResponse, error) {
+// A non-empty request ID makes the payment idempotent, so it will be retried on transient failures.
+// An empty one is made by the client's RequestIDs generator, if it has one.
+func (c *Client) Pay(id, account, cp, cpAccount, currency, reference, schedule string, amount Amount) (*PaymentResponse, error) {
+	return c.PayContext(context.Background(), id, account, cp, cpAccount, currency, reference, schedule, amount)
+}
+
+// PayContext is Pay with a context for deadlines and cancellation.
+func (c *Client) PayContext(ctx context.Context, id, account, cp, cpAccount, currency, reference, schedule string, amount Amount) (*PaymentResponse, error) {
+	currency = strings.ToUpper(currency)
+	_, err := amount.Minor(CurrencyExponent(currency))
+	if err != nil {
+		return nil, err
+	}
+
+	id, err = c.requestID(id, RequestDetails{
+		Account:         account,
+		Counterparty:    cp,
+		ReceiverAccount: cpAccount,
+		Amount:          amount,
+		Currency:        currency,
+		Reference:       reference,
+		Date:            ScheduleDate(schedule),
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	var req PaymentRequest
 	req.RequestID = id
 	req.AccountID = account
 	req.Receiver.CounterpartyID = cp
 	req.Receiver.AccountID = cpAccount
 	req.Amount = amount
-	req.Currency = strings.ToUpper(currency)
+	req.Currency = currency
 	req.Reference = reference
 	req.ScheduleTime = schedule
-	contents, code, err := c.PostJSON(epPay, req)
+	var resp PaymentResponse
+	err = c.do(ctx, "POST", epPay, req, &resp)
 	if err != nil {
 		return nil, err
 	}
 
-	if code != 200 {
-		return nil, jsonError(contents)
-	}
-
-	var resp PaymentResponse
-	err = json.Unmarshal(contents, &resp)
-	return &resp, err
+	return &resp, nil
 }
 
 // CancelPayment if possible.
 func (c *Client) CancelPayment(id string) error {
-	contents, code, err := c.Delete(epTransaction + "/" + id)
-	if err != nil {
-		return err
-	}
-
-	if code != 204 {
-		return jsonError(contents)
-	}
+	return c.CancelPaymentContext(context.Background(), id)
+}
 
-	return nil
+// CancelPaymentContext is CancelPayment with a context for deadlines and cancellation.
+func (c *Client) CancelPaymentContext(ctx context.Context, id string) error {
+	return c.do(ctx, "DELETE", epTransaction+"/"+id, nil, nil)
 }
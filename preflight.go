@@ -0,0 +1,196 @@
+package revolut
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Lookup finds the accounts and counterparties a payment or transfer is checked against.
+// The Client is a Lookup which asks the API each time. A Snapshot answers from lists fetched earlier.
+type Lookup interface {
+	GetAccountsContext(ctx context.Context) ([]Account, error)
+	GetCounterpartyContext(ctx context.Context, id string) (*Counterparty, error)
+}
+
+// Snapshot is a Lookup over accounts and counterparties fetched earlier, such as from a cache.
+// Balances in it may be out of date.
+type Snapshot struct {
+	// Accounts are your own accounts.
+	Accounts []Account
+	// Counterparties are the counterparties for the API key.
+	Counterparties []Counterparty
+}
+
+// Snapshot fetches your accounts and counterparties, to check several payments without
+// asking the API for each.
+func (c *Client) Snapshot() (*Snapshot, error) {
+	return c.SnapshotContext(context.Background())
+}
+
+// SnapshotContext is Snapshot with a context for deadlines and cancellation.
+func (c *Client) SnapshotContext(ctx context.Context) (*Snapshot, error) {
+	acc, err := c.GetAccountsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cps, err := c.GetCounterpartiesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Snapshot{Accounts: acc, Counterparties: cps}, nil
+}
+
+// GetAccountsContext returns the accounts in the snapshot.
+func (s *Snapshot) GetAccountsContext(ctx context.Context) ([]Account, error) {
+	return s.Accounts, nil
+}
+
+// GetCounterpartyContext finds a counterparty in the snapshot, returning ErrNotFound if it's missing.
+func (s *Snapshot) GetCounterpartyContext(ctx context.Context, id string) (*Counterparty, error) {
+	for i := range s.Counterparties {
+		if s.Counterparties[i].ID == id {
+			return &s.Counterparties[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("counterparty %s: %w", id, ErrNotFound)
+}
+
+// findAccount looks up one of your accounts by ID.
+func findAccount(list []Account, id string) *Account {
+	for i := range list {
+		if list[i].ID == id {
+			return &list[i]
+		}
+	}
+	return nil
+}
+
+// checkAmount adds errors for a currency which isn't an ISO code and an amount which
+// isn't positive or has too many decimals for the currency.
+func checkAmount(ve *ValidationError, amount Amount, currency string) {
+	ve.add("currency", ValidateCurrency(currency))
+	if amount.Sign() <= 0 {
+		ve.add("amount", fmt.Errorf("%w: %s", ErrAmountNotPositive, amount))
+		return
+	}
+
+	_, err := amount.Minor(CurrencyExponent(currency))
+	ve.add("amount", err)
+}
+
+// checkSource adds errors for an account which is missing, inactive or in another currency.
+// With a non-zero amount it also checks the balance covers it.
+func checkSource(ve *ValidationError, field string, acc *Account, id, currency string, amount Amount) {
+	if acc == nil {
+		ve.add(field, fmt.Errorf("%w: %s", ErrUnknownAccount, id))
+		return
+	}
+
+	if !acc.State.IsActive() {
+		ve.add(field, fmt.Errorf("%w: %s is %s", ErrAccountInactive, acc.Name, acc.State))
+	}
+
+	if acc.Currency != currency {
+		ve.add(field, fmt.Errorf("%w: %s holds %s, not %s", ErrCurrencyMismatch, acc.Name, acc.Currency, currency))
+		return
+	}
+
+	if !amount.IsZero() && acc.Balance.Cmp(amount) < 0 {
+		ve.add("amount", fmt.Errorf("%w: %s has %s %s", ErrInsufficientFunds, acc.Name, acc.Balance, acc.Currency))
+	}
+}
+
+// Validate checks the payment before it's sent: the amount and currency, that the account to pay
+// from exists, is active, holds the currency and has enough money, and that the counterparty exists
+// and has an account in the currency. The balance isn't checked for scheduled payments.
+// Problems are returned as a ValidationError, while a failing lookup returns its own error.
+func (r PaymentRequest) Validate(ctx context.Context, l Lookup) error {
+	var ve ValidationError
+	currency := strings.ToUpper(r.Currency)
+	checkAmount(&ve, r.Amount, currency)
+	accounts, err := l.GetAccountsContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	amount := r.Amount
+	if r.ScheduleTime != "" {
+		amount = Amount{}
+	}
+	checkSource(&ve, "account_id", findAccount(accounts, r.AccountID), r.AccountID, currency, amount)
+
+	cp, err := l.GetCounterpartyContext(ctx, r.Receiver.CounterpartyID)
+	if errors.Is(err, ErrNotFound) {
+		ve.add("receiver.counterparty_id", fmt.Errorf("%w: %s", ErrUnknownCounterparty, r.Receiver.CounterpartyID))
+		return ve.err()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if cp.State == CounterpartyDeleted {
+		ve.add("receiver.counterparty_id", fmt.Errorf("%w: %s was deleted", ErrUnknownCounterparty, cp.Name))
+	}
+
+	ve.add("receiver.account_id", checkReceiver(cp, r.Receiver.AccountID, currency))
+	return ve.err()
+}
+
+// checkReceiver checks that the chosen account of a counterparty, or any of them if none was chosen,
+// takes the currency. Counterparties without listed accounts can't be checked and pass.
+func checkReceiver(cp *Counterparty, id, currency string) error {
+	if id == "" {
+		if len(cp.Accounts) == 0 {
+			return nil
+		}
+
+		for _, acc := range cp.Accounts {
+			if acc.Currency == currency {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("%w: %s has no %s account", ErrCurrencyMismatch, cp.Name, currency)
+	}
+
+	for _, acc := range cp.Accounts {
+		if acc.ID != id {
+			continue
+		}
+
+		if acc.Currency != currency {
+			return fmt.Errorf("%w: %s account %s takes %s, not %s", ErrCurrencyMismatch, cp.Name, id, acc.Currency, currency)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s has no account %s", ErrUnknownAccount, cp.Name, id)
+}
+
+// Validate checks the transfer before it's sent: the amount and currency, that both accounts exist,
+// are active and hold the currency, that they differ, and that the source has enough money.
+// Problems are returned as a ValidationError, while a failing lookup returns its own error.
+func (r TransferRequest) Validate(ctx context.Context, l Lookup) error {
+	var ve ValidationError
+	currency := strings.ToUpper(r.Currency)
+	checkAmount(&ve, r.Amount, currency)
+	accounts, err := l.GetAccountsContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if r.SourceID == r.TargetID {
+		ve.add("target_account_id", ErrSameAccount)
+	}
+
+	checkSource(&ve, "source_account_id", findAccount(accounts, r.SourceID), r.SourceID, currency, r.Amount)
+	checkSource(&ve, "target_account_id", findAccount(accounts, r.TargetID), r.TargetID, currency, Amount{})
+	return ve.err()
+}
@@ -0,0 +1,135 @@
+package revolut
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTransferValidateCurrencyCase(t *testing.T) {
+	snap := &Snapshot{Accounts: []Account{
+		{ID: "a", Name: "Main", Currency: "GBP", State: AccountActive, Balance: NewAmount(10000, 2)},
+		{ID: "b", Name: "Savings", Currency: "GBP", State: AccountActive},
+	}}
+
+	for _, currency := range []string{"GBP", "gbp", "Gbp"} {
+		req := TransferRequest{SourceID: "a", TargetID: "b", Currency: currency, Amount: NewAmount(500, 2)}
+		err := req.Validate(context.Background(), snap)
+		if err != nil {
+			t.Errorf("%s: %v", currency, err)
+		}
+	}
+}
+
+// testSnapshot has accounts and counterparties for the pre-flight checks.
+func testSnapshot() *Snapshot {
+	return &Snapshot{
+		Accounts: []Account{
+			{ID: "gbp", Name: "Main", Currency: "GBP", State: AccountActive, Balance: NewAmount(10000, 2)},
+			{ID: "eur", Name: "Euro", Currency: "EUR", State: AccountActive, Balance: NewAmount(10000, 2)},
+			{ID: "old", Name: "Old", Currency: "GBP", State: AccountInactive, Balance: NewAmount(10000, 2)},
+			{ID: "save", Name: "Savings", Currency: "GBP", State: AccountActive},
+		},
+		Counterparties: []Counterparty{
+			{ID: "shop", Name: "Shop", State: CounterpartyCreated, Accounts: []CounterpartyAccount{
+				{ID: "shop-gbp", Currency: "GBP", Type: CounterpartyExternal},
+			}},
+			{ID: "friend", Name: "Friend", State: CounterpartyCreated, Accounts: []CounterpartyAccount{
+				{ID: "friend-eur", Currency: "EUR", Type: CounterpartyRevolut},
+			}},
+			{ID: "gone", Name: "Gone", State: CounterpartyDeleted, Accounts: []CounterpartyAccount{
+				{ID: "gone-gbp", Currency: "GBP", Type: CounterpartyExternal},
+			}},
+		},
+	}
+}
+
+func TestPaymentValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		account  string
+		cp       string
+		receiver string
+		amount   Amount
+		schedule string
+		field    string
+		want     error
+	}{
+		{name: "valid", account: "gbp", cp: "shop", amount: NewAmount(5000, 2)},
+		{name: "valid receiver", account: "gbp", cp: "shop", receiver: "shop-gbp", amount: NewAmount(5000, 2)},
+		{name: "zero", account: "gbp", cp: "shop", field: "amount", want: ErrAmountNotPositive},
+		{name: "too many decimals", account: "gbp", cp: "shop", amount: NewAmount(5001, 3), field: "amount", want: ErrAmountPrecision},
+		{name: "unknown account", account: "usd", cp: "shop", amount: NewAmount(5000, 2), field: "account_id", want: ErrUnknownAccount},
+		{name: "inactive account", account: "old", cp: "shop", amount: NewAmount(5000, 2), field: "account_id", want: ErrAccountInactive},
+		{name: "wrong account currency", account: "eur", cp: "shop", amount: NewAmount(5000, 2), field: "account_id", want: ErrCurrencyMismatch},
+		{name: "insufficient funds", account: "gbp", cp: "shop", amount: NewAmount(10001, 2), field: "amount", want: ErrInsufficientFunds},
+		{name: "scheduled skips balance", account: "gbp", cp: "shop", amount: NewAmount(10001, 2), schedule: "2030-01-01"},
+		{name: "unknown counterparty", account: "gbp", cp: "nobody", amount: NewAmount(5000, 2), field: "receiver.counterparty_id", want: ErrUnknownCounterparty},
+		{name: "deleted counterparty", account: "gbp", cp: "gone", amount: NewAmount(5000, 2), field: "receiver.counterparty_id", want: ErrUnknownCounterparty},
+		{name: "unknown receiver account", account: "gbp", cp: "shop", receiver: "shop-usd", amount: NewAmount(5000, 2), field: "receiver.account_id", want: ErrUnknownAccount},
+		{name: "wrong receiver currency", account: "gbp", cp: "friend", receiver: "friend-eur", amount: NewAmount(5000, 2), field: "receiver.account_id", want: ErrCurrencyMismatch},
+		{name: "no receiver account in currency", account: "gbp", cp: "friend", amount: NewAmount(5000, 2), field: "receiver.account_id", want: ErrCurrencyMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := PaymentRequest{
+				AccountID:    tt.account,
+				Receiver:     Receiver{CounterpartyID: tt.cp, AccountID: tt.receiver},
+				Amount:       tt.amount,
+				Currency:     "gbp",
+				ScheduleTime: tt.schedule,
+			}
+			checkValidation(t, req.Validate(context.Background(), testSnapshot()), tt.field, tt.want)
+		})
+	}
+}
+
+func TestTransferValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		target string
+		amount Amount
+		field  string
+		want   error
+	}{
+		{name: "valid", source: "gbp", target: "save", amount: NewAmount(5000, 2)},
+		{name: "inactive target", source: "gbp", target: "old", amount: NewAmount(5000, 2), field: "target_account_id", want: ErrAccountInactive},
+		{name: "inactive source", source: "old", target: "save", amount: NewAmount(5000, 2), field: "source_account_id", want: ErrAccountInactive},
+		{name: "same account", source: "gbp", target: "gbp", amount: NewAmount(5000, 2), field: "target_account_id", want: ErrSameAccount},
+		{name: "unknown target", source: "gbp", target: "usd", amount: NewAmount(5000, 2), field: "target_account_id", want: ErrUnknownAccount},
+		{name: "wrong target currency", source: "gbp", target: "eur", amount: NewAmount(5000, 2), field: "target_account_id", want: ErrCurrencyMismatch},
+		{name: "insufficient funds", source: "gbp", target: "save", amount: NewAmount(20000, 2), field: "amount", want: ErrInsufficientFunds},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := TransferRequest{SourceID: tt.source, TargetID: tt.target, Currency: "GBP", Amount: tt.amount}
+			checkValidation(t, req.Validate(context.Background(), testSnapshot()), tt.field, tt.want)
+		})
+	}
+}
+
+// checkValidation fails unless err is nil when want is, or a ValidationError with want for field.
+func checkValidation(t *testing.T, err error, field string, want error) {
+	t.Helper()
+	if want == nil {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		return
+	}
+
+	var ve ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("got %v, want a ValidationError", err)
+	}
+
+	for _, fe := range ve {
+		if fe.Field == field && errors.Is(fe, want) {
+			return
+		}
+	}
+	t.Errorf("got %v, want %s: %v", err, field, want)
+}
@@ -0,0 +1,156 @@
+package revolut
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Rate is an exchange rate. Unlike Amount it has no limit on digits, so any rate the API sends can be read.
+// The zero value is a rate of 0.
+type Rate struct {
+	rat   *big.Rat
+	scale int
+}
+
+// NewRate creates a Rate from an integer with exp decimal places. NewRate(15055, 2) is 150.55.
+func NewRate(value int64, exp int) Rate {
+	r := new(big.Rat).SetInt64(value)
+	d := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)
+	return Rate{rat: r.Quo(r, new(big.Rat).SetInt(d)), scale: exp}
+}
+
+// ParseRate reads a decimal string such as "1.2345". Exponents aren't accepted.
+func ParseRate(s string) (Rate, error) {
+	s = strings.TrimSpace(s)
+	digits := strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	parts := strings.SplitN(digits, ".", 2)
+	scale := 0
+	if len(parts) == 2 {
+		scale = len(parts[1])
+	}
+
+	if strings.Join(parts, "") == "" || strings.TrimLeft(strings.Join(parts, ""), "0123456789") != "" {
+		return Rate{}, fmt.Errorf("rate %q must be a decimal number", s)
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Rate{}, fmt.Errorf("rate %q must be a decimal number", s)
+	}
+
+	return Rate{rat: r, scale: scale}, nil
+}
+
+// String formats the rate with as many decimals as it was given.
+func (r Rate) String() string {
+	if r.rat == nil {
+		return "0"
+	}
+
+	return r.rat.FloatString(r.scale)
+}
+
+// IsZero is true for a rate of 0.
+func (r Rate) IsZero() bool {
+	return r.rat == nil || r.rat.Sign() == 0
+}
+
+// Rat returns the exact value of the rate.
+func (r Rate) Rat() *big.Rat {
+	if r.rat == nil {
+		return new(big.Rat)
+	}
+
+	return new(big.Rat).Set(r.rat)
+}
+
+// Float64 is an approximation for display and statistics only.
+func (r Rate) Float64() float64 {
+	f, _ := r.Rat().Float64()
+	return f
+}
+
+// Inverse returns 1/r, such as the rate for the other direction of an exchange. It's rounded for
+// display to the decimals of r, but at least six. The inverse of 0 is 0.
+func (r Rate) Inverse() Rate {
+	if r.IsZero() {
+		return Rate{}
+	}
+
+	scale := r.scale
+	if scale < 6 {
+		scale = 6
+	}
+	return Rate{rat: new(big.Rat).Inv(r.rat), scale: scale}
+}
+
+// Convert multiplies an amount by the rate, rounding halves away from zero to exp decimals.
+func (r Rate) Convert(a Amount, exp int) (Amount, error) {
+	x, ok := new(big.Rat).SetString(a.String())
+	if !ok {
+		return Amount{}, ErrAmountFormat
+	}
+
+	return ParseAmount(x.Mul(x, r.Rat()).FloatString(exp))
+}
+
+// MarshalJSON writes the rate as a JSON number.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON reads a JSON number, or a string holding one, without rounding.
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" || s == "" {
+		*r = Rate{}
+		return nil
+	}
+
+	// Accept exponent notation in case the API ever sends it.
+	if strings.ContainsAny(s, "eE") {
+		x, ok := new(big.Rat).SetString(s)
+		if !ok {
+			return fmt.Errorf("rate %q must be a decimal number", s)
+		}
+		s = x.FloatString(exponentDecimals(s))
+	}
+
+	x, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+
+	*r = x
+	return nil
+}
+
+// exponentDecimals returns how many decimals a valid number in exponent notation needs to be written exactly.
+func exponentDecimals(s string) int {
+	e := strings.IndexAny(s, "eE")
+	exp, _ := strconv.Atoi(s[e+1:])
+	n := 0
+	if dot := strings.IndexByte(s[:e], '.'); dot >= 0 {
+		n = e - dot - 1
+	}
+
+	n -= exp
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// decimals returns how many decimals x needs to be written exactly, up to 18.
+func decimals(x *big.Rat) int {
+	p := big.NewInt(1)
+	for n := 0; n < 18; n++ {
+		if new(big.Int).Mod(p, x.Denom()).Sign() == 0 {
+			return n
+		}
+		p.Mul(p, big.NewInt(10))
+	}
+	return 18
+}
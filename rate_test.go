@@ -0,0 +1,73 @@
+package revolut
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRateJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{`1.2345`, "1.2345", true},
+		{`"150.55"`, "150.55", true},
+		{`0.000012345678901234567890`, "0.000012345678901234567890", true},
+		{`123456789012345678.5`, "123456789012345678.5", true},
+		{`1.5e-3`, "0.0015", true},
+		{`1e-30`, "0.000000000000000000000000000001", true},
+		{`"1.23456789012345678901E-5"`, "0.0000123456789012345678901", true},
+		{`12.345e1`, "123.45", true},
+		{`1.5E+2`, "150", true},
+		{`null`, "0", true},
+		{`"abc"`, "", false},
+		{`"1/3"`, "", false},
+	}
+
+	for _, tt := range tests {
+		var r Rate
+		err := json.Unmarshal([]byte(tt.in), &r)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: error %v", tt.in, err)
+			continue
+		}
+		if tt.ok && r.String() != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.in, r, tt.want)
+		}
+	}
+}
+
+func TestExchangeRateManyDigits(t *testing.T) {
+	var rate ExchangeRate
+	err := json.Unmarshal([]byte(`{"from":{"amount":10,"currency":"GBP"},"to":{"amount":11.79,"currency":"EUR"},"rate":1.17912345678901234567}`), &rate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rate.Rate.String() != "1.17912345678901234567" {
+		t.Errorf("rate %s", rate.Rate)
+	}
+}
+
+func TestRateConvert(t *testing.T) {
+	tests := []struct {
+		rate   Rate
+		amount Amount
+		exp    int
+		want   string
+	}{
+		{NewRate(15055, 2), NewAmount(1000, 2), 0, "1506"},
+		{NewRate(8, 1), NewAmount(250, 2), 2, "2.00"},
+		{NewRate(8, 1).Inverse(), NewAmount(100, 2), 2, "1.25"},
+		{NewRate(3, 0).Inverse(), NewAmount(100, 2), 3, "0.333"},
+		{Rate{}, NewAmount(100, 2), 2, "0.00"},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.rate.Convert(tt.amount, tt.exp)
+		if err != nil || got.Format(tt.exp) != tt.want {
+			t.Errorf("%s * %s = %s, %v, want %s", tt.amount, tt.rate, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package revolut
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket which can be shared by all goroutines using a Client,
+// or by several clients using the same API key.
+type RateLimiter struct {
+	mu sync.Mutex
+	// rate is the number of tokens added per second.
+	rate float64
+	// burst is the bucket size.
+	burst float64
+	// tokens currently available. It goes negative when callers are queued.
+	tokens float64
+	// last refill time.
+	last time.Time
+}
+
+// NewRateLimiter creates a limiter allowing rps requests per second on average,
+// with bursts of up to burst requests. The bucket starts full.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request may be sent, or returns the context's error if it ends first.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	wait := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		// Hand the reserved token back for the next caller.
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,116 @@
+package revolut
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBurst(t *testing.T) {
+	l := NewRateLimiter(1, 3)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		err := l.Wait(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if d := time.Since(start); d > time.Millisecond*100 {
+		t.Errorf("burst of 3 took %v", d)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	err := l.Wait(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("fourth call: got %v, want deadline exceeded", err)
+	}
+}
+
+func TestRateLimiterRefill(t *testing.T) {
+	l := NewRateLimiter(50, 1)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		err := l.Wait(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The first call uses the full bucket, the other four wait 20ms each.
+	if d := time.Since(start); d < time.Millisecond*70 {
+		t.Errorf("5 calls at 50/s with burst 1 took only %v", d)
+	}
+}
+
+func TestRateLimiterShared(t *testing.T) {
+	l := NewRateLimiter(100, 2)
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := l.Wait(context.Background())
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Two go straight through, the other four are spaced 10ms apart.
+	if d := time.Since(start); d < time.Millisecond*35 {
+		t.Errorf("6 calls at 100/s with burst 2 took only %v", d)
+	}
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+	err := l.Wait(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(time.Millisecond * 20)
+		cancel()
+	}()
+
+	start := time.Now()
+	err = l.Wait(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context canceled", err)
+	}
+	if d := time.Since(start); d > time.Millisecond*500 {
+		t.Errorf("cancelled wait took %v", d)
+	}
+
+	// The cancelled call handed its reserved token back.
+	l.mu.Lock()
+	tokens := l.tokens
+	l.mu.Unlock()
+	if tokens < -0.5 {
+		t.Errorf("%.2f tokens after cancelling, want the reserved token returned", tokens)
+	}
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	l := NewRateLimiter(0, 1)
+	for i := 0; i < 10; i++ {
+		err := l.Wait(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context canceled", err)
+	}
+}
@@ -0,0 +1,83 @@
+package revolut
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// CorrelationHeader carries a unique ID for each API call, repeated on retries,
+// so requests can be matched up in logs on both ends.
+const CorrelationHeader = "X-Correlation-Id"
+
+type correlationKey struct{}
+
+// WithCorrelationID returns a context which makes calls use id in the CorrelationHeader
+// instead of a generated one. Use it to pass on the ID of an incoming request.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// correlationID returns the ID set on ctx, or a new random one.
+func correlationID(ctx context.Context) string {
+	id, ok := ctx.Value(correlationKey{}).(string)
+	if ok && id != "" {
+		return id
+	}
+
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// v2 returns the full URL of an endpoint in version 2.0 of the API, which holds the webhooks.
+// Base URLs without a version, such as some proxies, are used as they are.
+func (c *Client) v2(path string) string {
+	return strings.Replace(c.baseURL, "/1.0/", "/2.0/", 1) + path
+}
+
+// newRequest builds a request for an endpoint with every header the client is configured to send.
+// Default headers are applied first, so they can't replace authentication or content negotiation.
+// Paths which are full URLs, such as from v2, are used as they are.
+func (c *Client) newRequest(ctx context.Context, method, path string, body []byte, cid string) (*http.Request, error) {
+	var url strings.Builder
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		url.WriteString(c.baseURL)
+	}
+	url.WriteString(path)
+
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url.String(), r)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range c.Header {
+		req.Header[k] = append([]string(nil), v...)
+	}
+
+	req.Header.Set("Authorization", c.bearer)
+	req.Header.Set("Accept", "application/json")
+	if c.Agent != "" {
+		req.Header.Set("User-Agent", c.Agent)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if cid != "" {
+		req.Header.Set(CorrelationHeader, cid)
+	}
+	return req, nil
+}
@@ -0,0 +1,149 @@
+package revolut
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is a test server which keeps the headers of every request and fails the first few.
+type recorder struct {
+	mu      sync.Mutex
+	headers []http.Header
+	fail    int
+}
+
+func (rec *recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.headers = append(rec.headers, r.Header.Clone())
+	if len(rec.headers) <= rec.fail {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Write([]byte("{}"))
+}
+
+func newTestClient(t *testing.T, url string, opts ...Option) *Client {
+	t.Helper()
+	opts = append([]Option{WithBaseURL(url), WithRetryPolicy(RetryPolicy{MaxAttempts: 1})}, opts...)
+	c, err := NewClient("sand_testkey", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestRequestHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+	}{
+		{"get", "GET", ""},
+		{"post", "POST", "application/json"},
+		{"delete", "DELETE", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{}
+			srv := httptest.NewServer(rec)
+			defer srv.Close()
+
+			c := newTestClient(t, srv.URL,
+				WithUserAgent("test-agent/1.0"),
+				WithHeader("Authorization", "Bearer stolen"),
+				WithHeader("X-Extra", "yes"),
+			)
+
+			var err error
+			switch tt.method {
+			case "POST":
+				_, _, err = c.PostJSON("things", map[string]string{"a": "b"})
+			case "DELETE":
+				_, _, err = c.Delete("things/1")
+			default:
+				_, _, err = c.GetJSON("things")
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(rec.headers) != 1 {
+				t.Fatalf("got %d requests, want 1", len(rec.headers))
+			}
+
+			h := rec.headers[0]
+			want := map[string]string{
+				"User-Agent":    "test-agent/1.0",
+				"Accept":        "application/json",
+				"Content-Type":  tt.contentType,
+				"Authorization": "Bearer sand_testkey",
+				"X-Extra":       "yes",
+			}
+			for k, v := range want {
+				if got := h.Get(k); got != v {
+					t.Errorf("%s = %q, want %q", k, got, v)
+				}
+			}
+
+			if len(h["Authorization"]) != 1 {
+				t.Errorf("Authorization sent %d times", len(h["Authorization"]))
+			}
+
+			if h.Get(CorrelationHeader) == "" {
+				t.Errorf("no %s", CorrelationHeader)
+			}
+		})
+	}
+}
+
+func TestCorrelationID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"generated", ""},
+		{"given", "incoming-1234"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{fail: 2}
+			srv := httptest.NewServer(rec)
+			defer srv.Close()
+
+			c := newTestClient(t, srv.URL, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+			ctx := context.Background()
+			if tt.id != "" {
+				ctx = WithCorrelationID(ctx, tt.id)
+			}
+
+			_, _, err := c.GetJSONContext(ctx, "things")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(rec.headers) != 3 {
+				t.Fatalf("got %d requests, want 3", len(rec.headers))
+			}
+
+			first := rec.headers[0].Get(CorrelationHeader)
+			if tt.id != "" && first != tt.id {
+				t.Errorf("%s = %q, want %q", CorrelationHeader, first, tt.id)
+			}
+
+			for i, h := range rec.headers {
+				if got := h.Get(CorrelationHeader); got != first || got == "" {
+					t.Errorf("attempt %d: %s = %q, want %q", i+1, CorrelationHeader, got, first)
+				}
+			}
+		})
+	}
+}
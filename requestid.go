@@ -0,0 +1,136 @@
+package revolut
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxRequestIDLength is the longest request ID the API accepts.
+const MaxRequestIDLength = 40
+
+// RequestDetails describes a payment, transfer or exchange for a RequestIDGenerator.
+type RequestDetails struct {
+	// Account the money comes from.
+	Account string
+	// Counterparty receiving a payment, or the target account of a transfer or exchange.
+	Counterparty string
+	// ReceiverAccount is the counterparty's account a payment goes to, if it has several.
+	ReceiverAccount string
+	// Amount of money.
+	Amount Amount
+	// Currency of the amount.
+	Currency string
+	// Reference on the transaction.
+	Reference string
+	// Date is the business date the transaction belongs to. Only the day is used, as it is in the
+	// time's own location.
+	Date time.Time
+}
+
+// RequestIDGenerator makes the request IDs which stop payments, transfers and exchanges from
+// happening twice. Sending a request ID again returns the first transaction instead of a new one.
+type RequestIDGenerator interface {
+	RequestID(d RequestDetails) (string, error)
+}
+
+// UUIDv4 generates random request IDs. Every call gives a new one, so only the retries
+// of a single call are safe from doubling.
+type UUIDv4 struct{}
+
+// RequestID returns a random version 4 UUID.
+func (UUIDv4) RequestID(d RequestDetails) (string, error) {
+	var b [16]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return "", err
+	}
+
+	return formatUUID(b, 4), nil
+}
+
+// UUIDv7 generates random request IDs starting with the time, so they sort in the order they were made.
+type UUIDv7 struct {
+	// Now returns the current time. Leave it nil to use time.Now.
+	Now func() time.Time
+}
+
+// RequestID returns a version 7 UUID.
+func (g UUIDv7) RequestID(d RequestDetails) (string, error) {
+	now := time.Now()
+	if g.Now != nil {
+		now = g.Now()
+	}
+
+	var b [16]byte
+	_, err := rand.Read(b[6:])
+	if err != nil {
+		return "", err
+	}
+
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(now.UnixNano()/int64(time.Millisecond)))
+	copy(b[:6], ms[2:])
+	return formatUUID(b, 7), nil
+}
+
+// ContentHash generates request IDs from the details of the transaction, so the same payment on
+// the same business date always gets the same ID, on any machine. Two identical payments on one
+// day need different references, or explicit request IDs.
+type ContentHash struct {
+	// Namespace keeps IDs from different applications sharing an API key apart. It may be empty.
+	Namespace string
+	// Now returns the current time, used when the details have no Date. Its day is taken in UTC,
+	// so machines in different timezones agree. Leave it nil to use time.Now.
+	Now func() time.Time
+}
+
+// RequestID returns a version 8 UUID made from a SHA-256 hash of the namespace, account,
+// counterparty, receiver account, amount, currency, reference and date.
+func (g ContentHash) RequestID(d RequestDetails) (string, error) {
+	date := d.Date
+	if date.IsZero() {
+		date = time.Now()
+		if g.Now != nil {
+			date = g.Now()
+		}
+		date = date.UTC()
+	}
+
+	// Quoting keeps the fields from running into each other, and trimming makes 12.5 and 12.50 equal.
+	h := sha256.New()
+	fmt.Fprintf(h, "%q %q %q %q %q %q %q %q", g.Namespace, d.Account, d.Counterparty, d.ReceiverAccount,
+		d.Amount.trim(), strings.ToUpper(d.Currency), d.Reference, date.Format("2006-01-02"))
+	var b [16]byte
+	copy(b[:], h.Sum(nil))
+	return formatUUID(b, 8), nil
+}
+
+// formatUUID sets the version and variant bits and formats b as a UUID.
+func formatUUID(b [16]byte, version byte) string {
+	b[6] = (b[6] & 0x0f) | version<<4
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// requestID returns id, or a new one from the client's RequestIDs if it's empty and there is a generator.
+func (c *Client) requestID(id string, d RequestDetails) (string, error) {
+	if id != "" || c.RequestIDs == nil {
+		return id, nil
+	}
+
+	return c.RequestIDs.RequestID(d)
+}
+
+// ScheduleDate reads the day from a schedule_for time, or returns the zero time if there is none.
+func ScheduleDate(s string) time.Time {
+	if len(s) < 10 {
+		return time.Time{}
+	}
+
+	t, _ := time.Parse("2006-01-02", s[:10])
+	return t
+}
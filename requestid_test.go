@@ -0,0 +1,122 @@
+package revolut
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-([0-9a-f])[0-9a-f]{3}-([0-9a-f])[0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestUUIDFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		gen     RequestIDGenerator
+		version string
+	}{
+		{"UUIDv4", UUIDv4{}, "4"},
+		{"UUIDv7", UUIDv7{}, "7"},
+		{"ContentHash", ContentHash{}, "8"},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			s := id(t, tt.gen, RequestDetails{Reference: fmt.Sprint(i)})
+			m := uuidPattern.FindStringSubmatch(s)
+			if m == nil {
+				t.Fatalf("%s: %q isn't a UUID", tt.name, s)
+			}
+
+			if m[1] != tt.version {
+				t.Errorf("%s: %s has version %s, want %s", tt.name, s, m[1], tt.version)
+			}
+			if m[2] != "8" && m[2] != "9" && m[2] != "a" && m[2] != "b" {
+				t.Errorf("%s: %s doesn't have the RFC 4122 variant", tt.name, s)
+			}
+		}
+	}
+
+	if id(t, UUIDv4{}, RequestDetails{}) == id(t, UUIDv4{}, RequestDetails{}) {
+		t.Error("UUIDv4 made the same ID twice")
+	}
+}
+
+func TestUUIDv7Order(t *testing.T) {
+	start := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	var now time.Time
+	g := UUIDv7{Now: func() time.Time { return now }}
+
+	var ids []string
+	for _, d := range []time.Duration{0, time.Millisecond, time.Second, time.Hour, time.Hour * 24 * 365 * 10} {
+		now = start.Add(d)
+		ids = append(ids, id(t, g, RequestDetails{}))
+	}
+
+	if !sort.StringsAreSorted(ids) {
+		t.Errorf("IDs out of order: %v", ids)
+	}
+
+	// The first 48 bits are the Unix time in milliseconds.
+	want := fmt.Sprintf("%012x", start.UnixNano()/int64(time.Millisecond))
+	if got := ids[0][:8] + ids[0][9:13]; got != want {
+		t.Errorf("time bits %s, want %s", got, want)
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	base := RequestDetails{
+		Account:         "acc",
+		Counterparty:    "cp",
+		ReceiverAccount: "cp-acc-1",
+		Amount:          NewAmount(1250, 2),
+		Currency:        "GBP",
+		Reference:       "Invoice 1",
+		Date:            time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	other := base
+	other.ReceiverAccount = "cp-acc-2"
+	same := base
+	same.Amount = NewAmount(125, 1)
+	same.Currency = "gbp"
+
+	// The same instant is a different day in Oslo and New York, but not in UTC.
+	instant := time.Date(2020, 1, 2, 23, 30, 0, 0, time.UTC)
+	oslo := base
+	oslo.Date = time.Time{}
+	newYork := oslo
+	osloGen := ContentHash{Now: func() time.Time { return instant.In(time.FixedZone("CET", 3600)) }}
+	newYorkGen := ContentHash{Now: func() time.Time { return instant.In(time.FixedZone("EST", -5*3600)) }}
+
+	tests := []struct {
+		name  string
+		a, b  string
+		equal bool
+	}{
+		{"receiver account", id(t, ContentHash{}, base), id(t, ContentHash{}, other), false},
+		{"amount and currency form", id(t, ContentHash{}, base), id(t, ContentHash{}, same), true},
+		{"namespace", id(t, ContentHash{}, base), id(t, ContentHash{Namespace: "x"}, base), false},
+		{"timezone", id(t, osloGen, oslo), id(t, newYorkGen, newYork), true},
+	}
+
+	for _, tt := range tests {
+		if (tt.a == tt.b) != tt.equal {
+			t.Errorf("%s: %s and %s, want equal %v", tt.name, tt.a, tt.b, tt.equal)
+		}
+	}
+}
+
+func id(t *testing.T, g RequestIDGenerator, d RequestDetails) string {
+	t.Helper()
+	s, err := g.RequestID(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s) > MaxRequestIDLength {
+		t.Errorf("%s is longer than %d", s, MaxRequestIDLength)
+	}
+	return s
+}
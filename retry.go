@@ -0,0 +1,133 @@
+package revolut
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how transient failures are retried.
+// GET and DELETE requests are always eligible. Payments and transfers are only
+// retried when they carry a request ID, which makes them safe to repeat.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of tries, including the first. 0 or 1 disables retries.
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry. It doubles for each attempt after that.
+	BaseDelay time.Duration
+	// MaxDelay caps the exponential backoff. A Retry-After header from the server overrides it.
+	MaxDelay time.Duration
+	// MaxRetryAfter caps how long a Retry-After header can make the client wait.
+	// 0 means DefaultMaxRetryAfter.
+	MaxRetryAfter time.Duration
+	// Jitter is the fraction (0-1) of each backoff delay to randomise.
+	Jitter float64
+}
+
+// DefaultRetryPolicy is used by NewClient.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:   3,
+	BaseDelay:     time.Millisecond * 500,
+	MaxDelay:      time.Second * 10,
+	MaxRetryAfter: DefaultMaxRetryAfter,
+	Jitter:        0.2,
+}
+
+// DefaultMaxRetryAfter is the longest wait a server's Retry-After can cause unless the policy sets its own.
+const DefaultMaxRetryAfter = time.Minute
+
+// delay returns how long to wait before the next attempt. The server's
+// Retry-After takes precedence over the calculated backoff, up to MaxRetryAfter.
+func (p RetryPolicy) delay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		max := p.MaxRetryAfter
+		if max <= 0 {
+			max = DefaultMaxRetryAfter
+		}
+		if retryAfter > max {
+			return max
+		}
+		return retryAfter
+	}
+
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		j := p.Jitter
+		if j > 1 {
+			j = 1
+		}
+		d -= time.Duration(rand.Float64() * j * float64(d))
+	}
+	return d
+}
+
+// retryable decides if an error is worth another attempt: rate limiting,
+// server-side failures and transport errors, unless the context is done.
+// Errors such as a bad URL or an oversized response would only fail again.
+func retryable(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+
+	var e *APIError
+	if errors.As(err, &e) {
+		switch e.Status {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	return transportError(err)
+}
+
+// transportError reports if err is a network failure or a connection cut short.
+// The *url.Error from the HTTP client is unwrapped first, since it also wraps URL parsing errors.
+func transportError(err error) bool {
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		err = ue.Err
+	}
+
+	var ne net.Error
+	return errors.As(err, &ne) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// retryAfter parses a Retry-After header, which is either a number of seconds or a HTTP date.
+func retryAfter(s string) time.Duration {
+	if s == "" {
+		return 0
+	}
+
+	n, err := strconv.Atoi(s)
+	if err == nil {
+		if n < 0 {
+			return 0
+		}
+		return time.Duration(n) * time.Second
+	}
+
+	t, err := http.ParseTime(s)
+	if err != nil {
+		return 0
+	}
+
+	d := time.Until(t)
+	if d < 0 {
+		return 0
+	}
+	return d
+}
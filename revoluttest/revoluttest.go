@@ -0,0 +1,1059 @@
+// Package revoluttest provides an in-process fake of the Revolut for Business API for tests.
+//
+// The server keeps accounts, counterparties and transactions in memory. Payments and
+// transfers move balances and are recorded as transactions, so payment flows can be
+// tested deterministically:
+//
+//	srv := revoluttest.NewServer()
+//	defer srv.Close()
+//	gbp := srv.AddAccount(revolut.Account{Name: "Main", Currency: "GBP", Balance: revolut.NewAmount(100000, 2)})
+//	c, _ := srv.NewClient()
+//	list, _ := c.GetAccounts()
+package revoluttest
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Urethramancer/revolut"
+)
+
+// Key is the only API key the fake server accepts.
+const Key = "sand_revoluttest0000000000000000000000000000000000"
+
+// Server is a fake Revolut for Business API.
+type Server struct {
+	*httptest.Server
+	// Now returns the time used for new timestamps. Replace it for fixed times in tests.
+	Now func() time.Time
+	// InclusiveTo makes transaction lists include those created exactly at the "to" time.
+	// By default "to" is exclusive.
+	InclusiveTo bool
+
+	mu             sync.Mutex
+	accounts       []*revolut.Account
+	details        map[string][]revolut.BankDetails
+	counterparties []*revolut.Counterparty
+	transactions   []*revolut.TransactionStatus
+	webhooks       []*revolut.Webhook
+	rates          map[string]revolut.Rate
+}
+
+// NewServer starts a fake API server. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		Now:     time.Now,
+		details: make(map[string][]revolut.BankDetails),
+		rates:   make(map[string]revolut.Rate),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// NewClient returns a client using Key, pointed at the server. Options are applied after the base URL.
+func (s *Server) NewClient(opts ...revolut.Option) (*revolut.Client, error) {
+	opts = append([]revolut.Option{revolut.WithBaseURL(s.BaseURL())}, opts...)
+	return revolut.NewClient(Key, opts...)
+}
+
+// BaseURL returns the root of version 1.0 of the API on the server, for WithBaseURL.
+func (s *Server) BaseURL() string {
+	return s.URL + "/api/1.0/"
+}
+
+// AddAccount stores an account, giving it an ID and timestamps if missing. The stored copy is returned.
+func (s *Server) AddAccount(acc revolut.Account) revolut.Account {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if acc.ID == "" {
+		acc.ID = newUUID()
+	}
+	if acc.State == "" {
+		acc.State = revolut.AccountActive
+	}
+	if acc.Created.IsZero() {
+		acc.Created = s.timestamp()
+		acc.Updated = acc.Created
+	}
+	s.accounts = append(s.accounts, &acc)
+	return acc
+}
+
+// SetBankDetails sets the bank details returned for an account.
+func (s *Server) SetBankDetails(id string, det []revolut.BankDetails) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.details[id] = det
+}
+
+// AddCounterparty stores a counterparty, giving it and its accounts IDs if missing. The stored copy is returned.
+func (s *Server) AddCounterparty(cp revolut.Counterparty) revolut.Counterparty {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addCounterparty(&cp)
+	return cp
+}
+
+// Account returns the current state of an account.
+func (s *Server) Account(id string) (revolut.Account, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acc := s.account(id)
+	if acc == nil {
+		return revolut.Account{}, false
+	}
+	return *acc, true
+}
+
+// Transactions returns every recorded transaction, oldest first.
+func (s *Server) Transactions() []revolut.TransactionStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]revolut.TransactionStatus, len(s.transactions))
+	for i, t := range s.transactions {
+		list[i] = *t
+	}
+	return list
+}
+
+// SetTransactionState changes the state of a transaction, such as completing a pending payment.
+func (s *Server) SetTransactionState(id string, state revolut.TransactionState) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.transaction(id)
+	if t == nil {
+		return false
+	}
+
+	t.State = state
+	t.UpdatedAt = s.timestamp()
+	if state == revolut.StateCompleted {
+		t.CompletedAt = t.UpdatedAt
+	}
+	return true
+}
+
+// SetRate sets the exchange rate for selling one currency for another.
+func (s *Server) SetRate(from, to string, rate revolut.Rate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rates[from+"/"+to] = rate
+}
+
+// Webhooks returns the registered webhooks.
+func (s *Server) Webhooks() []revolut.Webhook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]revolut.Webhook, len(s.webhooks))
+	for i, hook := range s.webhooks {
+		list[i] = *hook
+	}
+	return list
+}
+
+//
+// Routing
+//
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+Key {
+		writeError(w, http.StatusUnauthorized, "invalid API key")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(p) < 3 || p[0] != "api" {
+		writeError(w, http.StatusNotFound, "unknown endpoint")
+		return
+	}
+
+	version := p[1]
+	p = p[2:]
+	route := r.Method + " " + p[0]
+	if version == "2.0" {
+		s.serveV2(w, r, route, p)
+		return
+	}
+
+	if version != "1.0" {
+		writeError(w, http.StatusNotFound, "unknown endpoint")
+		return
+	}
+
+	switch {
+	case route == "GET accounts" && len(p) == 1:
+		s.getAccounts(w)
+	case route == "GET accounts" && len(p) == 2:
+		s.getAccount(w, p[1])
+	case route == "GET accounts" && len(p) == 3 && p[2] == "bank-details":
+		s.getBankDetails(w, p[1])
+	case route == "GET counterparties" && len(p) == 1:
+		s.getCounterparties(w)
+	case route == "GET counterparty" && len(p) == 2:
+		s.getCounterparty(w, p[1])
+	case route == "POST counterparty" && len(p) == 1:
+		s.addCounterpartyRequest(w, r)
+	case route == "DELETE counterparty" && len(p) == 2:
+		s.deleteCounterparty(w, p[1])
+	case route == "POST pay" && len(p) == 1:
+		s.pay(w, r)
+	case route == "POST transfer" && len(p) == 1:
+		s.transfer(w, r)
+	case route == "GET transaction" && len(p) == 2:
+		s.getTransaction(w, p[1])
+	case route == "DELETE transaction" && len(p) == 2:
+		s.cancelTransaction(w, p[1])
+	case route == "GET transactions" && len(p) == 1:
+		s.getTransactions(w, r)
+	case route == "GET rate" && len(p) == 1:
+		s.getRate(w, r)
+	case route == "POST exchange" && len(p) == 1:
+		s.exchange(w, r)
+	default:
+		writeError(w, http.StatusNotFound, "unknown endpoint")
+	}
+}
+
+// serveV2 handles the endpoints of version 2.0 of the API, which only has webhooks.
+func (s *Server) serveV2(w http.ResponseWriter, r *http.Request, route string, p []string) {
+	switch {
+	case route == "POST webhooks" && len(p) == 1:
+		if hook := s.addWebhook(w, r); hook != nil {
+			writeJSON(w, http.StatusCreated, hook)
+		}
+	case route == "GET webhooks" && len(p) == 1:
+		s.getWebhooks(w)
+	case route == "GET webhooks" && len(p) == 2:
+		s.getWebhook(w, p[1])
+	case route == "PATCH webhooks" && len(p) == 2:
+		s.updateWebhook(w, r, p[1])
+	case route == "DELETE webhooks" && len(p) == 2:
+		s.deleteWebhook(w, p[1])
+	default:
+		writeError(w, http.StatusNotFound, "unknown endpoint")
+	}
+}
+
+//
+// Accounts
+//
+
+func (s *Server) getAccounts(w http.ResponseWriter) {
+	list := make([]revolut.Account, 0, len(s.accounts))
+	for _, acc := range s.accounts {
+		list = append(list, *acc)
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) getAccount(w http.ResponseWriter, id string) {
+	acc := s.account(id)
+	if acc == nil {
+		writeError(w, http.StatusNotFound, "account not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, acc)
+}
+
+func (s *Server) getBankDetails(w http.ResponseWriter, id string) {
+	if s.account(id) == nil {
+		writeError(w, http.StatusNotFound, "account not found")
+		return
+	}
+
+	det := s.details[id]
+	if det == nil {
+		det = []revolut.BankDetails{}
+	}
+	writeJSON(w, http.StatusOK, det)
+}
+
+//
+// Counterparties
+//
+
+func (s *Server) getCounterparties(w http.ResponseWriter) {
+	list := make([]revolut.Counterparty, 0, len(s.counterparties))
+	for _, cp := range s.counterparties {
+		list = append(list, *cp)
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) getCounterparty(w http.ResponseWriter, id string) {
+	cp := s.counterparty(id)
+	if cp == nil {
+		writeError(w, http.StatusNotFound, "counterparty not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, cp)
+}
+
+func (s *Server) addCounterpartyRequest(w http.ResponseWriter, r *http.Request) {
+	var fields map[string]json.RawMessage
+	var data json.RawMessage
+	if !readJSON(w, r, &data) || !decode(w, data, &fields) {
+		return
+	}
+
+	if _, ok := fields["bank_country"]; ok {
+		var ext revolut.ExternalCounterparty
+		if !decode(w, data, &ext) {
+			return
+		}
+
+		name := ext.Company
+		if name == "" && ext.Name != nil {
+			name = strings.TrimSpace(ext.Name.First + " " + ext.Name.Last)
+		}
+		if name == "" || ext.Currency == "" {
+			writeError(w, http.StatusBadRequest, "name and currency are required")
+			return
+		}
+
+		profile := revolut.ProfilePersonal
+		if ext.Company != "" {
+			profile = revolut.ProfileBusiness
+		}
+		cp := revolut.Counterparty{
+			Name:    name,
+			Phone:   ext.Phone,
+			Type:    profile,
+			Country: ext.BankCountry,
+			Accounts: []revolut.CounterpartyAccount{{
+				Currency: ext.Currency,
+				Type:     revolut.CounterpartyExternal,
+				Account:  ext.AccountNo,
+				SortCode: ext.SortCode,
+				Email:    ext.Email,
+				Name:     name,
+				Country:  ext.BankCountry,
+			}},
+		}
+		s.addCounterparty(&cp)
+		res := revolut.ExternalCounterpartyResponse{
+			ID:        cp.ID,
+			Name:      cp.Name,
+			State:     cp.State,
+			CreatedAt: cp.CreatedAt,
+			UpdatedAt: cp.UpdatedAt,
+		}
+		for _, acc := range cp.Accounts {
+			res.Accounts = append(res.Accounts, revolut.ExternalAccount{
+				ID:        acc.ID,
+				Currency:  acc.Currency,
+				Type:      acc.Type,
+				AccountNo: ext.AccountNo,
+				IBAN:      ext.IBAN,
+				SortCode:  ext.SortCode,
+				RoutingNo: ext.RoutingNo,
+				BIC:       ext.BIC,
+			})
+		}
+		writeJSON(w, http.StatusOK, res)
+		return
+	}
+
+	var in revolut.InternalCounterparty
+	if !decode(w, data, &in) {
+		return
+	}
+
+	if !in.ProfileType.Known() {
+		writeError(w, http.StatusBadRequest, "profile_type must be business or personal")
+		return
+	}
+
+	cp := revolut.Counterparty{
+		Name:  in.Name,
+		Phone: in.Phone,
+		Type:  in.ProfileType,
+	}
+	s.addCounterparty(&cp)
+	res := revolut.CounterpartyResponse{
+		ID:          cp.ID,
+		Name:        cp.Name,
+		Phone:       cp.Phone,
+		ProfileType: cp.Type,
+		State:       cp.State,
+		CreatedAt:   cp.CreatedAt,
+		UpdatedAt:   cp.UpdatedAt,
+		Accounts:    []revolut.Account{},
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) deleteCounterparty(w http.ResponseWriter, id string) {
+	for i, cp := range s.counterparties {
+		if cp.ID == id {
+			s.counterparties = append(s.counterparties[:i], s.counterparties[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "counterparty not found")
+}
+
+//
+// Payments and transfers
+//
+
+func (s *Server) pay(w http.ResponseWriter, r *http.Request) {
+	var req revolut.PaymentRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if t := s.byRequestID(req.RequestID); t != nil {
+		writeJSON(w, http.StatusOK, paymentResponse(t))
+		return
+	}
+
+	if req.RequestID == "" || req.Amount.Sign() <= 0 {
+		writeError(w, http.StatusBadRequest, "request_id and a positive amount are required")
+		return
+	}
+
+	acc := s.account(req.AccountID)
+	if acc == nil {
+		writeError(w, http.StatusNotFound, "account not found")
+		return
+	}
+
+	cp := s.counterparty(req.Receiver.CounterpartyID)
+	if cp == nil {
+		writeError(w, http.StatusNotFound, "counterparty not found")
+		return
+	}
+
+	when, ok := parseTime(req.ScheduleTime)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "schedule_for must be a date or RFC3339 time")
+		return
+	}
+
+	if !s.debit(w, acc, req.Currency, req.Amount) {
+		return
+	}
+
+	t := s.newTransaction(req.RequestID, req.Reference)
+	if req.ScheduleTime != "" {
+		t.State = revolut.StatePending
+		t.ScheduledTime = revolut.NewTime(when)
+	}
+	t.Legs = []revolut.Leg{{
+		ID:        newUUID(),
+		Amount:    req.Amount.Neg(),
+		Currency:  req.Currency,
+		AccountID: acc.ID,
+		Counterparty: revolut.LegCounterparty{
+			ID:        cp.ID,
+			Type:      counterpartyType(cp),
+			AccountID: req.Receiver.AccountID,
+		},
+		Description: "Payment to " + cp.Name,
+	}}
+	writeJSON(w, http.StatusOK, paymentResponse(t))
+}
+
+func (s *Server) transfer(w http.ResponseWriter, r *http.Request) {
+	var req revolut.TransferRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if t := s.byRequestID(req.ID); t != nil {
+		writeJSON(w, http.StatusOK, transferResponse(t))
+		return
+	}
+
+	if req.ID == "" || req.Amount.Sign() <= 0 {
+		writeError(w, http.StatusBadRequest, "request_id and a positive amount are required")
+		return
+	}
+
+	src := s.account(req.SourceID)
+	dst := s.account(req.TargetID)
+	if src == nil || dst == nil {
+		writeError(w, http.StatusNotFound, "account not found")
+		return
+	}
+
+	if dst.Currency != req.Currency {
+		writeError(w, http.StatusBadRequest, "target account currency doesn't match")
+		return
+	}
+
+	if !s.move(w, src, dst, req.Currency, req.Amount, req.Amount) {
+		return
+	}
+	t := s.newTransaction(req.ID, req.Reference)
+	t.Legs = []revolut.Leg{
+		{
+			ID:           newUUID(),
+			Amount:       req.Amount.Neg(),
+			Currency:     req.Currency,
+			AccountID:    src.ID,
+			Counterparty: revolut.LegCounterparty{Type: revolut.CounterpartySelf, AccountID: dst.ID},
+			Description:  "To " + dst.Name,
+		},
+		{
+			ID:           newUUID(),
+			Amount:       req.Amount,
+			Currency:     req.Currency,
+			AccountID:    dst.ID,
+			Counterparty: revolut.LegCounterparty{Type: revolut.CounterpartySelf, AccountID: src.ID},
+			Description:  "From " + src.Name,
+		},
+	}
+	writeJSON(w, http.StatusOK, transferResponse(t))
+}
+
+// debit takes money from an account, writing an error response if that isn't possible.
+func (s *Server) debit(w http.ResponseWriter, acc *revolut.Account, currency string, amount revolut.Amount) bool {
+	if acc.Currency != currency {
+		writeError(w, http.StatusBadRequest, "account currency doesn't match")
+		return false
+	}
+
+	if acc.Balance.Cmp(amount) < 0 {
+		writeError(w, http.StatusBadRequest, "insufficient balance")
+		return false
+	}
+
+	bal, err := acc.Balance.Sub(amount)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	acc.Balance = bal
+	acc.Updated = s.timestamp()
+	return true
+}
+
+// move debits sell from src and credits buy to dst, writing an error response if that isn't possible.
+func (s *Server) move(w http.ResponseWriter, src, dst *revolut.Account, currency string, sell, buy revolut.Amount) bool {
+	old := src.Balance
+	if !s.debit(w, src, currency, sell) {
+		return false
+	}
+
+	bal, err := dst.Balance.Add(buy)
+	if err != nil {
+		src.Balance = old
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	dst.Balance = bal
+	return true
+}
+
+//
+// Exchange
+//
+
+func (s *Server) getRate(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	from, to := q.Get("from"), q.Get("to")
+	rate, ok := s.rates[from+"/"+to]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "no rate for "+from+"/"+to)
+		return
+	}
+
+	amount := revolut.NewAmount(1, 0)
+	if a := q.Get("amount"); a != "" {
+		var err error
+		amount, err = revolut.ParseAmount(a)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, revolut.ExchangeRate{
+		From:     revolut.ExchangeAmount{Amount: amount, Currency: from},
+		To:       revolut.ExchangeAmount{Amount: convert(amount, rate, to, false), Currency: to},
+		Rate:     rate,
+		Fee:      revolut.ExchangeAmount{Amount: revolut.NewAmount(0, 0), Currency: from},
+		RateDate: s.timestamp(),
+	})
+}
+
+func (s *Server) exchange(w http.ResponseWriter, r *http.Request) {
+	var req revolut.ExchangeRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if t := s.byRequestID(req.RequestID); t != nil {
+		writeJSON(w, http.StatusOK, exchangeResponse(t, revolut.Rate{}))
+		return
+	}
+
+	if req.RequestID == "" || (req.From.Amount == nil) == (req.To.Amount == nil) {
+		writeError(w, http.StatusBadRequest, "request_id and an amount on one side are required")
+		return
+	}
+
+	src := s.account(req.From.AccountID)
+	dst := s.account(req.To.AccountID)
+	if src == nil || dst == nil {
+		writeError(w, http.StatusNotFound, "account not found")
+		return
+	}
+
+	if dst.Currency != req.To.Currency {
+		writeError(w, http.StatusBadRequest, "target account currency doesn't match")
+		return
+	}
+
+	rate, ok := s.rates[req.From.Currency+"/"+req.To.Currency]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "no rate for "+req.From.Currency+"/"+req.To.Currency)
+		return
+	}
+
+	var sell, buy revolut.Amount
+	if req.From.Amount != nil {
+		sell = *req.From.Amount
+		buy = convert(sell, rate, req.To.Currency, false)
+	} else {
+		buy = *req.To.Amount
+		sell = convert(buy, rate, req.From.Currency, true)
+	}
+
+	if sell.Sign() <= 0 {
+		writeError(w, http.StatusBadRequest, "amount must be positive")
+		return
+	}
+
+	if !s.move(w, src, dst, req.From.Currency, sell, buy) {
+		return
+	}
+	t := s.newTransaction(req.RequestID, req.Reference)
+	t.Type = revolut.TypeExchange
+	t.Legs = []revolut.Leg{
+		{
+			ID:           newUUID(),
+			Amount:       sell.Neg(),
+			Currency:     req.From.Currency,
+			AccountID:    src.ID,
+			Counterparty: revolut.LegCounterparty{Type: revolut.CounterpartySelf, AccountID: dst.ID},
+			Description:  "Exchanged to " + req.To.Currency,
+		},
+		{
+			ID:           newUUID(),
+			Amount:       buy,
+			Currency:     req.To.Currency,
+			AccountID:    dst.ID,
+			Counterparty: revolut.LegCounterparty{Type: revolut.CounterpartySelf, AccountID: src.ID},
+			Description:  "Exchanged from " + req.From.Currency,
+		},
+	}
+	writeJSON(w, http.StatusOK, exchangeResponse(t, rate))
+}
+
+// convert applies an exchange rate, or its inverse, rounding to the decimals of the resulting currency.
+func convert(a revolut.Amount, rate revolut.Rate, currency string, inverse bool) revolut.Amount {
+	if inverse {
+		rate = rate.Inverse()
+	}
+
+	res, _ := rate.Convert(a, revolut.CurrencyExponent(currency))
+	return res
+}
+
+//
+// Transactions
+//
+
+func (s *Server) getTransaction(w http.ResponseWriter, id string) {
+	t := s.transaction(id)
+	if t == nil {
+		writeError(w, http.StatusNotFound, "transaction not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, t)
+}
+
+func (s *Server) cancelTransaction(w http.ResponseWriter, id string) {
+	t := s.transaction(id)
+	if t == nil {
+		writeError(w, http.StatusNotFound, "transaction not found")
+		return
+	}
+
+	if t.State != revolut.StatePending {
+		writeError(w, http.StatusBadRequest, "only pending transactions can be cancelled")
+		return
+	}
+
+	// Return the money to the paying account.
+	for _, l := range t.Legs {
+		if acc := s.account(l.AccountID); acc != nil {
+			if bal, err := acc.Balance.Sub(l.Amount); err == nil {
+				acc.Balance = bal
+			}
+		}
+	}
+	t.State = revolut.StateDeclined
+	t.Reason = "cancelled"
+	t.UpdatedAt = s.timestamp()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getTransactions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	from, okFrom := parseTime(q.Get("from"))
+	to, okTo := parseTime(q.Get("to"))
+	if !okFrom || !okTo {
+		writeError(w, http.StatusBadRequest, "from and to must be dates or RFC3339 times")
+		return
+	}
+
+	count := 100
+	if c := q.Get("count"); c != "" {
+		n, err := strconv.Atoi(c)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "count must be a positive number")
+			return
+		}
+		count = n
+	}
+
+	types := q["type"]
+	cpID := q.Get("counterparty")
+	accID := q.Get("account")
+	list := []revolut.TransactionStatus{}
+	for i := len(s.transactions) - 1; i >= 0 && len(list) < count; i-- {
+		t := s.transactions[i]
+		if !from.IsZero() && t.CreatedAt.Before(from) {
+			continue
+		}
+		if !to.IsZero() && (t.CreatedAt.After(to) || (!s.InclusiveTo && t.CreatedAt.Equal(to))) {
+			continue
+		}
+		if len(types) > 0 && !contains(types, t.Type.String()) {
+			continue
+		}
+		if cpID != "" && !hasCounterparty(t, cpID) {
+			continue
+		}
+		if accID != "" && !hasAccount(t, accID) {
+			continue
+		}
+		list = append(list, *t)
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+//
+// Webhooks
+//
+
+// addWebhook stores a webhook from the request, or writes an error and returns nil.
+func (s *Server) addWebhook(w http.ResponseWriter, r *http.Request) *revolut.Webhook {
+	var req revolut.WebhookRequest
+	if !readJSON(w, r, &req) {
+		return nil
+	}
+
+	if !strings.HasPrefix(req.URL, "https://") {
+		writeError(w, http.StatusBadRequest, "webhook URL must use https")
+		return nil
+	}
+
+	hook := &revolut.Webhook{
+		ID:            newUUID(),
+		URL:           req.URL,
+		Events:        req.Events,
+		SigningSecret: "wsk_" + strings.Replace(newUUID(), "-", "", -1),
+	}
+	s.webhooks = append(s.webhooks, hook)
+	return hook
+}
+
+func (s *Server) getWebhooks(w http.ResponseWriter) {
+	list := make([]revolut.Webhook, 0, len(s.webhooks))
+	for _, hook := range s.webhooks {
+		list = append(list, *hook)
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) getWebhook(w http.ResponseWriter, id string) {
+	hook := s.webhook(id)
+	if hook == nil {
+		writeError(w, http.StatusNotFound, "webhook not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, hook)
+}
+
+func (s *Server) updateWebhook(w http.ResponseWriter, r *http.Request, id string) {
+	hook := s.webhook(id)
+	if hook == nil {
+		writeError(w, http.StatusNotFound, "webhook not found")
+		return
+	}
+
+	var req revolut.WebhookRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if req.URL != "" {
+		if !strings.HasPrefix(req.URL, "https://") {
+			writeError(w, http.StatusBadRequest, "webhook URL must use https")
+			return
+		}
+		hook.URL = req.URL
+	}
+	if len(req.Events) > 0 {
+		hook.Events = req.Events
+	}
+	writeJSON(w, http.StatusOK, hook)
+}
+
+func (s *Server) deleteWebhook(w http.ResponseWriter, id string) {
+	for i, hook := range s.webhooks {
+		if hook.ID == id {
+			s.webhooks = append(s.webhooks[:i], s.webhooks[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "webhook not found")
+}
+
+//
+// State helpers. The caller holds the lock.
+//
+
+func (s *Server) timestamp() revolut.Time {
+	return revolut.NewTime(s.Now().UTC())
+}
+
+func (s *Server) account(id string) *revolut.Account {
+	for _, acc := range s.accounts {
+		if acc.ID == id {
+			return acc
+		}
+	}
+	return nil
+}
+
+func (s *Server) counterparty(id string) *revolut.Counterparty {
+	for _, cp := range s.counterparties {
+		if cp.ID == id {
+			return cp
+		}
+	}
+	return nil
+}
+
+func (s *Server) addCounterparty(cp *revolut.Counterparty) {
+	if cp.ID == "" {
+		cp.ID = newUUID()
+	}
+	if cp.State == "" {
+		cp.State = revolut.CounterpartyCreated
+	}
+	if cp.CreatedAt.IsZero() {
+		cp.CreatedAt = s.timestamp()
+		cp.UpdatedAt = cp.CreatedAt
+	}
+	for i := range cp.Accounts {
+		if cp.Accounts[i].ID == "" {
+			cp.Accounts[i].ID = newUUID()
+		}
+	}
+	s.counterparties = append(s.counterparties, cp)
+}
+
+func (s *Server) webhook(id string) *revolut.Webhook {
+	for _, hook := range s.webhooks {
+		if hook.ID == id {
+			return hook
+		}
+	}
+	return nil
+}
+
+func (s *Server) transaction(id string) *revolut.TransactionStatus {
+	for _, t := range s.transactions {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+func (s *Server) byRequestID(id string) *revolut.TransactionStatus {
+	if id == "" {
+		return nil
+	}
+
+	for _, t := range s.transactions {
+		if t.RequestID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+func (s *Server) newTransaction(requestID, reference string) *revolut.TransactionStatus {
+	now := s.timestamp()
+	t := &revolut.TransactionStatus{
+		ID:          newUUID(),
+		Type:        revolut.TypeTransfer,
+		RequestID:   requestID,
+		State:       revolut.StateCompleted,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		CompletedAt: now,
+		Reference:   reference,
+	}
+	s.transactions = append(s.transactions, t)
+	return t
+}
+
+//
+// Conversions
+//
+
+func paymentResponse(t *revolut.TransactionStatus) revolut.PaymentResponse {
+	res := revolut.PaymentResponse{
+		ID:        t.ID,
+		State:     t.State,
+		Reason:    t.Reason,
+		CreatedAt: t.CreatedAt,
+	}
+	if t.State == revolut.StateCompleted {
+		res.CompletedAt = t.CompletedAt
+	}
+	return res
+}
+
+func transferResponse(t *revolut.TransactionStatus) revolut.TransferResponse {
+	return revolut.TransferResponse{
+		ID:          t.ID,
+		State:       t.State,
+		CreatedAt:   t.CreatedAt,
+		CompletedAt: t.CompletedAt,
+	}
+}
+
+func exchangeResponse(t *revolut.TransactionStatus, rate revolut.Rate) revolut.ExchangeResponse {
+	return revolut.ExchangeResponse{
+		ID:          t.ID,
+		Type:        t.Type,
+		State:       t.State,
+		Reason:      t.Reason,
+		CreatedAt:   t.CreatedAt,
+		CompletedAt: t.CompletedAt,
+		Rate:        rate,
+	}
+}
+
+func counterpartyType(cp *revolut.Counterparty) revolut.CounterpartyType {
+	for _, acc := range cp.Accounts {
+		if acc.Type == revolut.CounterpartyExternal {
+			return revolut.CounterpartyExternal
+		}
+	}
+	return revolut.CounterpartyRevolut
+}
+
+func hasCounterparty(t *revolut.TransactionStatus, id string) bool {
+	for _, l := range t.Legs {
+		if l.Counterparty.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAccount(t *revolut.TransactionStatus, id string) bool {
+	for _, l := range t.Legs {
+		if l.AccountID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTime accepts the date and time formats the API does. An empty string is the zero time.
+func parseTime(s string) (time.Time, bool) {
+	if s == "" {
+		return time.Time{}, true
+	}
+
+	t, err := revolut.ParseTime(s)
+	return t, err == nil
+}
+
+//
+// Wire helpers
+//
+
+// maxBody is the largest request body the server will read.
+const maxBody = 1 << 20
+
+func readJSON(w http.ResponseWriter, r *http.Request, out interface{}) bool {
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody)).Decode(out)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func decode(w http.ResponseWriter, data []byte, out interface{}) bool {
+	err := json.Unmarshal(data, out)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, revolut.ErrorResponse{Message: msg, Code: status * 10})
+}
+
+// newUUID returns a random version 4 UUID.
+func newUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,101 @@
+package revolut
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RuleKey selects the CounterpartyRule for a bank country and currency.
+type RuleKey struct {
+	// Country is the two-letter bank country.
+	Country string
+	// Currency is the three-letter account currency. Leave it empty to match any currency.
+	Currency string
+}
+
+// CounterpartyRule lists the bank details an external counterparty must have, and those it can't have,
+// by their JSON field names.
+type CounterpartyRule struct {
+	// Name describes the kind of account in error messages, such as "UK GBP".
+	Name string
+	// Required fields must be set.
+	Required []string
+	// Disallowed fields must be empty.
+	Disallowed []string
+}
+
+// CounterpartyRules holds the rules for local bank transfers, keyed by bank country and currency.
+// Other countries use IBANRule or SWIFTRule.
+var CounterpartyRules = map[RuleKey]CounterpartyRule{
+	{"GB", "GBP"}: {
+		Name:       "UK GBP",
+		Required:   []string{"account_no", "sort_code"},
+		Disallowed: []string{"iban", "bic", "routing_number"},
+	},
+	{"US", "USD"}: {
+		Name:       "US USD",
+		Required:   []string{"account_no", "routing_number"},
+		Disallowed: []string{"iban", "bic", "sort_code"},
+	},
+}
+
+// IBANRule applies to countries with IBANs, unless CounterpartyRules has an entry.
+var IBANRule = CounterpartyRule{
+	Name:       "IBAN",
+	Required:   []string{"iban", "bic"},
+	Disallowed: []string{"account_no", "sort_code", "routing_number"},
+}
+
+// SWIFTRule applies to all other countries.
+var SWIFTRule = CounterpartyRule{
+	Name:       "SWIFT",
+	Required:   []string{"account_no", "bic"},
+	Disallowed: []string{"iban", "sort_code", "routing_number"},
+}
+
+// RuleFor returns the rule for a bank country and currency. An entry in CounterpartyRules for the
+// exact currency comes first, then one for any currency, then IBANRule or SWIFTRule. Case is ignored.
+func RuleFor(country, currency string) CounterpartyRule {
+	country, currency = strings.ToUpper(country), strings.ToUpper(currency)
+	rule, ok := CounterpartyRules[RuleKey{country, currency}]
+	if ok {
+		return rule
+	}
+
+	rule, ok = CounterpartyRules[RuleKey{Country: country}]
+	if ok {
+		return rule
+	}
+
+	if IBANLength(country) > 0 {
+		return IBANRule
+	}
+
+	return SWIFTRule
+}
+
+// bankFields returns the bank details of a counterparty by JSON field name.
+func (cp ExternalCounterparty) bankFields() map[string]string {
+	return map[string]string{
+		"account_no":     cp.AccountNo,
+		"sort_code":      cp.SortCode,
+		"routing_number": cp.RoutingNo,
+		"iban":           cp.IBAN,
+		"bic":            cp.BIC,
+	}
+}
+
+// check adds an error for every required field which is empty and every disallowed field which is set.
+func (r CounterpartyRule) check(fields map[string]string, ve *ValidationError) {
+	for _, f := range r.Required {
+		if fields[f] == "" {
+			ve.add(f, fmt.Errorf("%w for %s accounts", ErrFieldRequired, r.Name))
+		}
+	}
+
+	for _, f := range r.Disallowed {
+		if fields[f] != "" {
+			ve.add(f, fmt.Errorf("%w for %s accounts", ErrFieldNotAllowed, r.Name))
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package revolut
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRuleFor(t *testing.T) {
+	old := CounterpartyRules
+	defer func() { CounterpartyRules = old }()
+	CounterpartyRules = map[RuleKey]CounterpartyRule{}
+	for k, v := range old {
+		CounterpartyRules[k] = v
+	}
+	CounterpartyRules[RuleKey{Country: "GB"}] = CounterpartyRule{Name: "UK other"}
+
+	tests := []struct {
+		country  string
+		currency string
+		want     string
+	}{
+		{"GB", "GBP", "UK GBP"},
+		{"gb", "gbp", "UK GBP"},
+		{"GB", "EUR", "UK other"},
+		{"US", "USD", "US USD"},
+		{"us", "Usd", "US USD"},
+		{"US", "EUR", "SWIFT"},
+		{"DE", "EUR", "IBAN"},
+		{"de", "EUR", "IBAN"},
+		{"CN", "CNY", "SWIFT"},
+	}
+
+	for _, tt := range tests {
+		if got := RuleFor(tt.country, tt.currency).Name; got != tt.want {
+			t.Errorf("RuleFor(%q, %q) = %s, want %s", tt.country, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestCounterpartyRules(t *testing.T) {
+	tests := []struct {
+		name string
+		cp   ExternalCounterparty
+		want map[string]error
+	}{
+		{"UK GBP", ExternalCounterparty{BankCountry: "GB", Currency: "GBP", SortCode: "089999", AccountNo: "66374958"}, nil},
+		{"US USD", ExternalCounterparty{BankCountry: "US", Currency: "USD", RoutingNo: "021000021", AccountNo: "12345678"}, nil},
+		{"IBAN", ExternalCounterparty{BankCountry: "FR", Currency: "EUR", IBAN: "DE89370400440532013000", BIC: "DEUTDEFF"}, nil},
+		{"SWIFT", ExternalCounterparty{BankCountry: "CN", Currency: "CNY", AccountNo: "6222020200112233", BIC: "ICBKCNBJ"}, nil},
+		{"UK EUR uses IBAN", ExternalCounterparty{BankCountry: "GB", Currency: "EUR", IBAN: "GB82WEST12345698765432", BIC: "NWBKGB2L"}, nil},
+		{"UK GBP with IBAN details", ExternalCounterparty{BankCountry: "GB", Currency: "GBP", IBAN: "GB82WEST12345698765432", BIC: "NWBKGB2L"}, map[string]error{
+			"account_no": ErrFieldRequired,
+			"sort_code":  ErrFieldRequired,
+			"iban":       ErrFieldNotAllowed,
+			"bic":        ErrFieldNotAllowed,
+		}},
+		{"US USD missing routing number", ExternalCounterparty{BankCountry: "US", Currency: "USD", AccountNo: "12345678", SortCode: "089999"}, map[string]error{
+			"routing_number": ErrFieldRequired,
+			"sort_code":      ErrFieldNotAllowed,
+		}},
+		{"IBAN missing BIC", ExternalCounterparty{BankCountry: "DE", Currency: "EUR", IBAN: "DE89370400440532013000", AccountNo: "0532013000"}, map[string]error{
+			"bic":        ErrFieldRequired,
+			"account_no": ErrFieldNotAllowed,
+		}},
+		// The account number isn't checked as a UK one outside the UK.
+		{"SWIFT with sort code", ExternalCounterparty{BankCountry: "CN", Currency: "CNY", AccountNo: "6222020200112233", BIC: "ICBKCNBJ", SortCode: "089999"}, map[string]error{
+			"sort_code": ErrFieldNotAllowed,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cp.Company = "Acme"
+			err := tt.cp.Validate()
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var ve ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("got %v, want a ValidationError", err)
+			}
+
+			if len(ve) != len(tt.want) {
+				t.Errorf("got %v, want %d field errors", err, len(tt.want))
+			}
+			for _, fe := range ve {
+				if want, ok := tt.want[fe.Field]; !ok || !errors.Is(fe, want) {
+					t.Errorf("unexpected %v", fe)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package revolut
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Time is a timestamp from the API. It accepts both RFC3339 times and YYYY-MM-DD dates,
+// and embeds time.Time so it can be compared, sorted and formatted directly.
+type Time struct {
+	time.Time
+}
+
+// dateFormat is the date-only form used for filters and scheduled payments.
+const dateFormat = "2006-01-02"
+
+// ErrTimeFormat means a timestamp was neither RFC3339 nor YYYY-MM-DD.
+var ErrTimeFormat = errors.New("time must be RFC3339 or YYYY-MM-DD")
+
+// ParseTime reads an RFC3339 time or a YYYY-MM-DD date, which is taken as midnight UTC.
+func ParseTime(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err == nil {
+		return t, nil
+	}
+
+	t, err = time.Parse(dateFormat, s)
+	if err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, ErrTimeFormat
+}
+
+// NewTime wraps a time.Time.
+func NewTime(t time.Time) Time {
+	return Time{Time: t}
+}
+
+// MarshalJSON writes RFC3339 with fractional seconds, or null for the zero time.
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return []byte(`"` + t.Format(time.RFC3339Nano) + `"`), nil
+}
+
+// UnmarshalJSON accepts RFC3339, YYYY-MM-DD, an empty string or null.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		*t = Time{}
+		return nil
+	}
+
+	if len(s) < 2 || !strings.HasPrefix(s, `"`) || !strings.HasSuffix(s, `"`) {
+		return ErrTimeFormat
+	}
+
+	s = s[1 : len(s)-1]
+	if s == "" {
+		*t = Time{}
+		return nil
+	}
+
+	x, err := ParseTime(s)
+	if err != nil {
+		return err
+	}
+
+	t.Time = x
+	return nil
+}
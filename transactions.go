@@ -1,9 +1,12 @@
 package revolut
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
 	"strconv"
-	"strings"
+	"time"
 )
 
 // Merchant info.
@@ -18,26 +21,26 @@ type Merchant struct {
 	Country string `json:"country"`
 }
 
-// TransactionStatus is returned by GetTransaction() and GetTransactions().
+// TransactionStatus is returned by TransactionStatus() and ListTransactions().
 type TransactionStatus struct {
 	// ID of the transaction.
 	ID string `json:"id"`
 	// Type of transaction.
-	Type string `json:"type"`
+	Type TransactionType `json:"type"`
 	// RequestID provided by the client.
 	RequestID string `json:"request_id"`
 	// State is one of "pending", "completed", "declined" or "failed".
-	State string `json:"state"`
+	State TransactionState `json:"state"`
 	// Reason code for the "declined" and "failed" states.
-	Reason string `json:"reason_code"`
+	Reason ReasonCode `json:"reason_code"`
 	// CreatedAt is an ISO date/time.
-	CreatedAt string `json:"created_at"`
+	CreatedAt Time `json:"created_at"`
 	// UpdatedAt is an ISO date/time. Available when looking up transactions.
-	UpdatedAt string `json:"updated_at,omitempty"`
+	UpdatedAt Time `json:"updated_at,omitempty"`
 	// CompletedAt is an ISO date/time.
-	CompletedAt string `json:"completed_at,omitempty"`
+	CompletedAt Time `json:"completed_at,omitempty"`
 	// Scheduled time is an ISO date/time the transaction was scheduled to run.
-	ScheduledTime string `json:"scheduled_for"`
+	ScheduledTime Time `json:"scheduled_for"`
 	// Merchant info.
 	Merchant Merchant `json:"merchant"`
 	// Reference for the payment provided by the user.
@@ -50,12 +53,12 @@ type TransactionStatus struct {
 type Leg struct {
 	// ID of this leg.
 	ID string `json:"leg_id"`
-	// Amount of the transactiob.
-	Amount float64 `json:"amount"`
+	// Amount of the transaction.
+	Amount Amount `json:"amount"`
 	// Currency is the 3-letter ISO code for the transaction currency.
 	Currency string `json:"currency"`
 	// BillAmount is the amount for cross-currency transactions.
-	BillAmount float64 `json:"bill_amount,omitempty"`
+	BillAmount Amount `json:"bill_amount"`
 	// BillCurrency is the billing currency for cross-currency transactions.
 	BillCurrency string `json:"bill_currency,omitempty"`
 	// AccountID of the account this transaction is associated with.
@@ -72,8 +75,8 @@ type Leg struct {
 type LegCounterparty struct {
 	ID string `json:"id"`
 	// Type is "self", "revolut" or "external".
-	Type      string `json:"type"`
-	AccountID string `json:"account_id"`
+	Type      CounterpartyType `json:"type"`
+	AccountID string           `json:"account_id"`
 }
 
 // TransactionCreatedEvent is posted to webooks when a new transaction has been created.
@@ -81,7 +84,7 @@ type TransactionCreatedEvent struct {
 	// Event is the event name ("TransactionCreated").
 	Event string `json:"event"`
 	// Timestamp is the RFC3339 date and time of this event.
-	Timestamp string `json:"timestamp"`
+	Timestamp Time `json:"timestamp"`
 	// Data is the real payload.
 	Data TransactionCreatedData `json:"data"`
 }
@@ -91,19 +94,19 @@ type TransactionCreatedData struct {
 	// ID of the transaction.
 	ID string `json:"id"`
 	// Type of transaction.
-	Type string `json:"type"`
+	Type TransactionType `json:"type"`
 	// RequestID provided by the client.
 	RequestID string `json:"request_id"`
 	// State of the transaction.
-	State string `json:"state"`
+	State TransactionState `json:"state"`
 	// Reason for failure.
-	Reason string `json:"reason_code,omitempty"`
+	Reason ReasonCode `json:"reason_code,omitempty"`
 	// CreatedAt timestamp.
-	CreatedAt string `json:"created_at,omitempty"`
+	CreatedAt Time `json:"created_at,omitempty"`
 	// UpdatedAt timestamp.
-	UpdatedAt string `json:"updated_at,omitempty"`
+	UpdatedAt Time `json:"updated_at,omitempty"`
 	// CompletedAt timestamp.
-	CompletedAt string `json:"completed_at,omitempty"`
+	CompletedAt Time `json:"completed_at,omitempty"`
 	// Reference
 	Reference string `json:"reference,omitempty"`
 	// Legs of the route
@@ -115,7 +118,7 @@ type TransactionChangedEvent struct {
 	// Event is the event name ("TransactionCreated").
 	Event string `json:"event"`
 	// Timestamp is the RFC3339 date and time of this event.
-	Timestamp string `json:"timestamp"`
+	Timestamp Time `json:"timestamp"`
 	// Data is the real payload.
 	Data TransactionChangedData `json:"data"`
 }
@@ -125,78 +128,176 @@ type TransactionChangedData struct {
 	// ID of the transaction.
 	ID string `json:"id"`
 	// OldState before this event.
-	OldState string `json:"old_state"`
+	OldState TransactionState `json:"old_state"`
 	// NewState after this event. Expected states are "pending", "completed", "declined" or "failed".
-	NewState string `json:"new_state"`
+	NewState TransactionState `json:"new_state"`
 }
 
-// GetTransactions by optional filters. The from and to datescan be in the formats
-// YYYY-MM-DD or RFC3339.
-func (c *Client) GetTransactions(ttype, from, to, counterparty string, count int64) ([]TransactionStatus, error) {
-	var args strings.Builder
-	if count > 0 {
-		args.WriteString("count=")
-		arg := strconv.FormatInt(count, 10)
-		args.WriteString(arg)
-	}
+// MaxTransactionCount is the most transactions the API returns for one query.
+const MaxTransactionCount = 1000
+
+// TransactionQuery holds the optional filters for ListTransactions. Zero values are left out.
+type TransactionQuery struct {
+	// Types to include. Each must be Known().
+	Types []TransactionType
+	// From is the earliest creation time to include.
+	From time.Time
+	// To is the creation time to stop before.
+	To time.Time
+	// Counterparty is the UUID of a counterparty to show transactions for.
+	Counterparty string
+	// Account is the UUID of one of your accounts to show transactions for.
+	Account string
+	// Count is the maximum number of transactions, up to MaxTransactionCount.
+	Count int
+}
 
-	if len(ttype) > 0 {
-		if args.Len() > 0 {
-			args.WriteString("&")
+// Validate checks the query before it's sent.
+func (q TransactionQuery) Validate() error {
+	for _, t := range q.Types {
+		if !t.Known() {
+			return fmt.Errorf("unknown transaction type %q", t)
 		}
-		args.WriteString("type=")
-		args.WriteString(ttype)
 	}
 
-	if len(from) > 0 {
-		if args.Len() > 0 {
-			args.WriteString("&")
-		}
-		args.WriteString("from=")
-		args.WriteString(from)
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return errors.New("from must be before to")
 	}
 
-	if len(to) > 0 {
-		if args.Len() > 0 {
-			args.WriteString("&")
-		}
-		args.WriteString("to=")
-		args.WriteString(to)
+	if q.Count < 0 || q.Count > MaxTransactionCount {
+		return fmt.Errorf("count must be between 0 and %d", MaxTransactionCount)
 	}
 
-	var url strings.Builder
-	url.WriteString(epTransactions)
-	if args.Len() > 0 {
-		url.WriteString("?")
-		url.WriteString(args.String())
+	return nil
+}
+
+// Encode returns the URL-encoded query string, without the leading "?".
+func (q TransactionQuery) Encode() string {
+	v := url.Values{}
+	for _, t := range q.Types {
+		v.Add("type", t.String())
+	}
+	if !q.From.IsZero() {
+		v.Set("from", q.From.Format(time.RFC3339Nano))
+	}
+	if !q.To.IsZero() {
+		v.Set("to", q.To.Format(time.RFC3339Nano))
+	}
+	if q.Counterparty != "" {
+		v.Set("counterparty", q.Counterparty)
 	}
+	if q.Account != "" {
+		v.Set("account", q.Account)
+	}
+	if q.Count > 0 {
+		v.Set("count", strconv.Itoa(q.Count))
+	}
+	return v.Encode()
+}
 
-	contents, code, err := c.GetJSON(url.String())
+// ListTransactions returns transactions matching the query, newest first.
+func (c *Client) ListTransactions(ctx context.Context, q TransactionQuery) ([]TransactionStatus, error) {
+	err := q.Validate()
 	if err != nil {
 		return nil, err
 	}
 
-	if code != 200 {
-		return nil, jsonError(contents)
+	path := epTransactions
+	if args := q.Encode(); args != "" {
+		path += "?" + args
 	}
 
 	var list []TransactionStatus
-	err = json.Unmarshal(contents, &list)
-	return list, err
+	err = c.do(ctx, "GET", path, nil, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// ErrStopIteration can be returned from an IterateTransactions callback to end early without an error.
+var ErrStopIteration = errors.New("stop iteration")
+
+// ErrPageTooSmall is returned by IterateTransactions when a whole page shares one timestamp,
+// so the next page can't be told apart from it. Use a larger page size.
+var ErrPageTooSmall = errors.New("more transactions share a timestamp than fit in one page")
+
+// IterateTransactions calls fn for every transaction matching q, newest first, across as many
+// requests as needed. Each page is fetched with q.Count (or MaxTransactionCount) as the limit,
+// and the next page ends at the oldest creation time seen so far. Transactions appearing in
+// more than one page are only passed to fn once. Iteration stops at q.From, when fn returns
+// an error, or when the history runs out. Use a page size larger than the number of
+// transactions that can share one timestamp, or some of those may be missed. If the API treats
+// "to" as inclusive and a whole page shares one timestamp, ErrPageTooSmall is returned.
+func (c *Client) IterateTransactions(ctx context.Context, q TransactionQuery, fn func(TransactionStatus) error) error {
+	if q.Count == 0 {
+		q.Count = MaxTransactionCount
+	}
+
+	seen := make(map[string]bool)
+	for {
+		page, err := c.ListTransactions(ctx, q)
+		if err != nil {
+			return err
+		}
+
+		fresh := 0
+		var oldest time.Time
+		for _, t := range page {
+			if oldest.IsZero() || t.CreatedAt.Before(oldest) {
+				oldest = t.CreatedAt.Time
+			}
+
+			if seen[t.ID] || (!q.From.IsZero() && t.CreatedAt.Before(q.From)) {
+				continue
+			}
+
+			seen[t.ID] = true
+			fresh++
+			err = fn(t)
+			if err == ErrStopIteration {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(page) < q.Count || oldest.IsZero() || (!q.From.IsZero() && oldest.Before(q.From)) {
+			return nil
+		}
+
+		// Overlap by a nanosecond so transactions sharing the oldest timestamp aren't skipped,
+		// unless that would make no progress. Others may share a timestamp equal to From too.
+		next := oldest.Add(time.Nanosecond)
+		if fresh == 0 || (!q.To.IsZero() && !next.Before(q.To)) {
+			next = oldest
+		}
+		if !q.From.IsZero() && !next.After(q.From) {
+			return nil
+		}
+
+		// Asking again with the same end would get the same page back.
+		if fresh == 0 && !q.To.IsZero() && !next.Before(q.To) {
+			return ErrPageTooSmall
+		}
+		q.To = next
+	}
 }
 
 // TransactionStatus of transfers or payments.
 func (c *Client) TransactionStatus(id string) (*TransactionStatus, error) {
-	contents, code, err := c.GetJSON(epTransaction + "/" + id)
+	return c.TransactionStatusContext(context.Background(), id)
+}
+
+// TransactionStatusContext is TransactionStatus with a context for deadlines and cancellation.
+func (c *Client) TransactionStatusContext(ctx context.Context, id string) (*TransactionStatus, error) {
+	var resp TransactionStatus
+	err := c.do(ctx, "GET", epTransaction+"/"+id, nil, &resp)
 	if err != nil {
 		return nil, err
 	}
 
-	if code != 200 {
-		return nil, jsonError(contents)
-	}
-
-	var resp TransactionStatus
-	err = json.Unmarshal(contents, &resp)
-	return &resp, err
+	return &resp, nil
 }
@@ -0,0 +1,127 @@
+package revolut_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Urethramancer/revolut"
+	"github.com/Urethramancer/revolut/revoluttest"
+)
+
+// history makes one transfer per reference, each created at the matching time.
+// Times must be in ascending order. Close the server when done.
+func history(t *testing.T, refs []string, times []time.Time) (*revoluttest.Server, *revolut.Client) {
+	t.Helper()
+	srv := revoluttest.NewServer()
+	src := srv.AddAccount(revolut.Account{Name: "Main", Currency: "GBP", Balance: revolut.NewAmount(100000, 2)})
+	dst := srv.AddAccount(revolut.Account{Name: "Savings", Currency: "GBP"})
+	c, err := srv.NewClient()
+	if err != nil {
+		srv.Close()
+		t.Fatal(err)
+	}
+
+	for i, ref := range refs {
+		now := times[i]
+		srv.Now = func() time.Time { return now }
+		_, err = c.Transfer("req-"+ref, src.ID, dst.ID, "GBP", ref, revolut.NewAmount(100, 2))
+		if err != nil {
+			srv.Close()
+			t.Fatal(err)
+		}
+	}
+	return srv, c
+}
+
+func TestIterateTransactions(t *testing.T) {
+	base := time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(min int) time.Time { return base.Add(time.Minute * time.Duration(min)) }
+	// Three transactions share the time at minute 2, which spans the page boundary.
+	refs := []string{"t1", "t2a", "t2b", "t2c", "t3", "t4", "t5"}
+	times := []time.Time{at(1), at(2), at(2), at(2), at(3), at(4), at(5)}
+	srv, c := history(t, refs, times)
+	defer srv.Close()
+
+	tests := []struct {
+		name string
+		from time.Time
+		stop int
+		fail error
+		want string
+	}{
+		{name: "all pages", want: "t5 t4 t3 t2c t2b t2a t1"},
+		{name: "from", from: at(2), want: "t5 t4 t3 t2c t2b t2a"},
+		{name: "from between pages", from: at(3), want: "t5 t4 t3"},
+		{name: "stop", stop: 4, want: "t5 t4 t3 t2c"},
+		{name: "error", stop: 2, fail: errors.New("callback failed"), want: "t5 t4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			q := revolut.TransactionQuery{From: tt.from, Count: 3}
+			err := c.IterateTransactions(context.Background(), q, func(ts revolut.TransactionStatus) error {
+				got = append(got, ts.Reference)
+				if len(got) == tt.stop {
+					if tt.fail != nil {
+						return tt.fail
+					}
+					return revolut.ErrStopIteration
+				}
+				return nil
+			})
+			if err != tt.fail {
+				t.Errorf("got error %v, want %v", err, tt.fail)
+			}
+
+			if s := strings.Join(got, " "); s != tt.want {
+				t.Errorf("got %q, want %q", s, tt.want)
+			}
+		})
+	}
+}
+
+func TestIterateTransactionsInclusiveTo(t *testing.T) {
+	base := time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(min int) time.Time { return base.Add(time.Minute * time.Duration(min)) }
+	refs := []string{"t1", "t2a", "t2b", "t2c", "t3", "t4", "t5"}
+	times := []time.Time{at(1), at(2), at(2), at(2), at(3), at(4), at(5)}
+	srv, c := history(t, refs, times)
+	defer srv.Close()
+	srv.InclusiveTo = true
+
+	tests := []struct {
+		name  string
+		count int
+		err   error
+		want  string
+	}{
+		{"larger page", 4, nil, "t5 t4 t3 t2c t2b t2a t1"},
+		// A full page of transactions at minute 2 would come back again and again.
+		{"page of one timestamp", 3, revolut.ErrPageTooSmall, "t5 t4 t3 t2c t2b t2a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+			defer cancel()
+
+			var got []string
+			q := revolut.TransactionQuery{Count: tt.count}
+			err := c.IterateTransactions(ctx, q, func(ts revolut.TransactionStatus) error {
+				got = append(got, ts.Reference)
+				return nil
+			})
+			if err != tt.err {
+				t.Errorf("got error %v, want %v", err, tt.err)
+			}
+
+			if s := strings.Join(got, " "); s != tt.want {
+				t.Errorf("got %q, want %q", s, tt.want)
+			}
+		})
+	}
+}
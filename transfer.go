@@ -1,13 +1,14 @@
 package revolut
 
 import (
-	"encoding/json"
+	"context"
+	"strings"
 )
 
 // TransferRequest for money transfers within a business.
 type TransferRequest struct {
 	// Amount to transfer.
-	Amount float64 `json:"amount"`
+	Amount Amount `json:"amount"`
 	// ID of the request, provided by the client.
 	ID string `json:"request_id"`
 	// SourceID of the account to transfer from.
@@ -20,20 +21,48 @@ type TransferRequest struct {
 	Reference string `json:"reference"`
 }
 
+func (r TransferRequest) requestID() string {
+	return r.ID
+}
+
 // TransferResponse to a transfer request.
 type TransferResponse struct {
 	// ID of the created transaction.
 	ID string `json:"id"`
 	// State of the transaction. One of the following: "pending", "completed", "declined" or "failed".
-	State string `json:"state"`
+	State TransactionState `json:"state"`
 	// CreatedAT ISO date/time.
-	CreatedAt string `json:"created_at"`
+	CreatedAt Time `json:"created_at"`
 	// CompletedAt ISO date/time.
-	CompletedAt string `json:"completed_at"`
+	CompletedAt Time `json:"completed_at"`
 }
 
 // Transfer money between own accounts.
-func (c *Client) Transfer(id, sid, tid, currency, reference string, amount float64) (*TransferResponse, error) {
+// A non-empty request ID makes the transfer idempotent, so it will be retried on transient failures.
+// An empty one is made by the client's RequestIDs generator, if it has one.
+func (c *Client) Transfer(id, sid, tid, currency, reference string, amount Amount) (*TransferResponse, error) {
+	return c.TransferContext(context.Background(), id, sid, tid, currency, reference, amount)
+}
+
+// TransferContext is Transfer with a context for deadlines and cancellation.
+func (c *Client) TransferContext(ctx context.Context, id, sid, tid, currency, reference string, amount Amount) (*TransferResponse, error) {
+	currency = strings.ToUpper(currency)
+	_, err := amount.Minor(CurrencyExponent(currency))
+	if err != nil {
+		return nil, err
+	}
+
+	id, err = c.requestID(id, RequestDetails{
+		Account:      sid,
+		Counterparty: tid,
+		Amount:       amount,
+		Currency:     currency,
+		Reference:    reference,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	var req TransferRequest
 	req.ID = id
 	req.SourceID = sid
@@ -41,16 +70,11 @@ func (c *Client) Transfer(id, sid, tid, currency, reference string, amount float
 	req.Amount = amount
 	req.Currency = currency
 	req.Reference = reference
-	contents, code, err := c.PostJSON(epTransfer, req)
+	var resp TransferResponse
+	err = c.do(ctx, "POST", epTransfer, req, &resp)
 	if err != nil {
 		return nil, err
 	}
 
-	if code != 200 {
-		return nil, jsonError(contents)
-	}
-
-	var resp TransferResponse
-	err = json.Unmarshal(contents, &resp)
-	return &resp, err
+	return &resp, nil
 }
@@ -1,5 +1,10 @@
 package revolut
 
+import (
+	"errors"
+	"strings"
+)
+
 // ValidKey checks that the supplied string conforms roughly to a valid Revolut API key's format.
 func ValidKey(s string) bool {
 	// Too short, so definitely wrong.
@@ -19,10 +24,61 @@ func ValidKey(s string) bool {
 
 // ValidTransactionType checks if a type filter is correct.
 func ValidTransactionType(t string) bool {
-	switch t {
-	case "atm", "card_payment", "card_refund", "card_chargeback", "card_credit", "exchange", "transfer", "loan", "fee", "refund", "topup", "topup_return", "tax", "tax_refund":
-		return true
+	return TransactionType(t).Known()
+}
+
+// FieldError is a validation failure for one field of a request.
+type FieldError struct {
+	// Field is the JSON name of the field.
+	Field string
+	// Err is the reason, usually wrapping one of the validation errors.
+	Err error
+}
+
+// Error returns the field name and reason.
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+// Unwrap returns the reason.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError lists every problem found in a request.
+type ValidationError []*FieldError
+
+// Error joins the field errors.
+func (e ValidationError) Error() string {
+	list := make([]string, len(e))
+	for i, fe := range e {
+		list[i] = fe.Error()
 	}
+	return strings.Join(list, "; ")
+}
 
+// Is lets errors.Is() match any of the field errors.
+func (e ValidationError) Is(target error) bool {
+	for _, fe := range e {
+		if errors.Is(fe, target) {
+			return true
+		}
+	}
 	return false
 }
+
+// add appends a FieldError if err isn't nil.
+func (e *ValidationError) add(field string, err error) {
+	if err != nil {
+		*e = append(*e, &FieldError{Field: field, Err: err})
+	}
+}
+
+// err returns the list as an error, or nil if it's empty.
+func (e ValidationError) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
@@ -0,0 +1,200 @@
+package revolut
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// WaitOptions controls how often transactions are polled for changes.
+type WaitOptions struct {
+	// Interval is the first wait between polls. It doubles while nothing changes. Defaults to 2 seconds.
+	Interval time.Duration
+	// MaxInterval caps the wait between polls. Defaults to 1 minute.
+	MaxInterval time.Duration
+}
+
+// DefaultWaitOptions are used for fields left at 0.
+var DefaultWaitOptions = WaitOptions{
+	Interval:    time.Second * 2,
+	MaxInterval: time.Minute,
+}
+
+// first returns the starting interval.
+func (o WaitOptions) first() time.Duration {
+	if o.Interval <= 0 {
+		return DefaultWaitOptions.Interval
+	}
+
+	return o.Interval
+}
+
+// next returns the interval to use after d.
+func (o WaitOptions) next(d time.Duration) time.Duration {
+	max := o.MaxInterval
+	if max <= 0 {
+		max = DefaultWaitOptions.MaxInterval
+	}
+
+	d *= 2
+	if d > max {
+		d = max
+	}
+	return d
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// WaitForTransaction polls a transaction until its state IsTerminal ("completed", "declined",
+// "failed" or "reverted") and returns it. Use a context with a deadline to give up eventually.
+func (c *Client) WaitForTransaction(ctx context.Context, id string, opts WaitOptions) (*TransactionStatus, error) {
+	d := opts.first()
+	var state TransactionState
+	for {
+		t, err := c.TransactionStatusContext(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if t.State.IsTerminal() {
+			return t, nil
+		}
+
+		if t.State != state {
+			state = t.State
+			d = opts.first()
+		}
+
+		err = sleep(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+
+		d = opts.next(d)
+	}
+}
+
+// ErrWatcherStarted is returned by Watcher.Run when it has been called before.
+var ErrWatcherStarted = errors.New("watcher has already been run")
+
+// Watcher polls a set of transactions and reports their state changes.
+type Watcher struct {
+	client  *Client
+	opts    WaitOptions
+	changes chan TransactionChangedData
+	mu      sync.Mutex
+	states  map[string]TransactionState
+	started bool
+}
+
+// NewWatcher creates a Watcher. Add transactions to it, then Run it.
+func (c *Client) NewWatcher(opts WaitOptions) *Watcher {
+	return &Watcher{
+		client:  c,
+		opts:    opts,
+		changes: make(chan TransactionChangedData),
+		states:  make(map[string]TransactionState),
+	}
+}
+
+// Add transactions to watch. It's safe to call while the Watcher runs.
+func (w *Watcher) Add(ids ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, id := range ids {
+		if _, ok := w.states[id]; !ok {
+			w.states[id] = ""
+		}
+	}
+}
+
+// Changes returns the channel state changes are sent on. The first change for each
+// transaction has an empty OldState. The channel is closed when Run returns.
+func (w *Watcher) Changes() <-chan TransactionChangedData {
+	return w.changes
+}
+
+// Run polls until every transaction has reached a final state, the context is done,
+// or a lookup fails. The polling interval backs off while nothing changes.
+// Read from Changes while it runs. A Watcher can only be run once; later calls
+// return ErrWatcherStarted.
+func (w *Watcher) Run(ctx context.Context) error {
+	w.mu.Lock()
+	started := w.started
+	w.started = true
+	w.mu.Unlock()
+	if started {
+		return ErrWatcherStarted
+	}
+
+	defer close(w.changes)
+	d := w.opts.first()
+	for {
+		ids := w.pending()
+		if len(ids) == 0 {
+			return nil
+		}
+
+		changed := false
+		for _, id := range ids {
+			t, err := w.client.TransactionStatusContext(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			w.mu.Lock()
+			old := w.states[id]
+			w.states[id] = t.State
+			w.mu.Unlock()
+			if t.State == old {
+				continue
+			}
+
+			changed = true
+			select {
+			case w.changes <- TransactionChangedData{ID: id, OldState: old, NewState: t.State}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if changed {
+			d = w.opts.first()
+		}
+
+		if len(w.pending()) == 0 {
+			return nil
+		}
+
+		err := sleep(ctx, d)
+		if err != nil {
+			return err
+		}
+
+		d = w.opts.next(d)
+	}
+}
+
+// pending lists the transactions not yet in a final state.
+func (w *Watcher) pending() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var ids []string
+	for id, state := range w.states {
+		if !state.IsTerminal() {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,145 @@
+package revolut_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Urethramancer/revolut"
+	"github.com/Urethramancer/revolut/revoluttest"
+)
+
+var fastPolls = revolut.WaitOptions{Interval: time.Millisecond, MaxInterval: time.Millisecond * 5}
+
+// scheduled makes n scheduled payments, which stay pending until their state is changed,
+// and returns their transaction IDs. Close the server when done.
+func scheduled(t *testing.T, n int) (*revoluttest.Server, *revolut.Client, []string) {
+	t.Helper()
+	srv := revoluttest.NewServer()
+	acc := srv.AddAccount(revolut.Account{Name: "Main", Currency: "GBP", Balance: revolut.NewAmount(100000, 2)})
+	cp := srv.AddCounterparty(revolut.Counterparty{Name: "Landlord"})
+	c, err := srv.NewClient()
+	if err != nil {
+		srv.Close()
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for i := 0; i < n; i++ {
+		day := fmt.Sprintf("2030-01-%02d", i+1)
+		res, err := c.Pay("rent-"+day, acc.ID, cp.ID, "", "GBP", "rent", day, revolut.NewAmount(1000, 2))
+		if err != nil {
+			srv.Close()
+			t.Fatal(err)
+		}
+		ids = append(ids, res.ID)
+	}
+	return srv, c, ids
+}
+
+func TestWaitForTransaction(t *testing.T) {
+	srv, c, ids := scheduled(t, 1)
+	defer srv.Close()
+
+	go func() {
+		time.Sleep(time.Millisecond * 20)
+		srv.SetTransactionState(ids[0], revolut.StateCompleted)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	ts, err := c.WaitForTransaction(ctx, ids[0], fastPolls)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ts.State != revolut.StateCompleted {
+		t.Errorf("state %s, want %s", ts.State, revolut.StateCompleted)
+	}
+}
+
+func TestWaitForTransactionErrors(t *testing.T) {
+	srv, c, ids := scheduled(t, 1)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*30)
+	defer cancel()
+	_, err := c.WaitForTransaction(ctx, ids[0], fastPolls)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("still pending: got %v, want deadline exceeded", err)
+	}
+
+	_, err = c.WaitForTransaction(context.Background(), "missing", fastPolls)
+	if !errors.Is(err, revolut.ErrNotFound) {
+		t.Errorf("unknown transaction: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	srv, c, ids := scheduled(t, 2)
+	defer srv.Close()
+
+	w := c.NewWatcher(fastPolls)
+	w.Add(ids...)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+
+	got := make(map[string][]string)
+	first := 0
+	for ch := range w.Changes() {
+		got[ch.ID] = append(got[ch.ID], string(ch.OldState)+">"+string(ch.NewState))
+		if ch.OldState != "" {
+			continue
+		}
+
+		// Settle the payments once both have been seen pending.
+		first++
+		if first == len(ids) {
+			srv.SetTransactionState(ids[0], revolut.StateCompleted)
+			srv.SetTransactionState(ids[1], revolut.StateDeclined)
+		}
+	}
+
+	err := <-done
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{ids[0]: "completed", ids[1]: "declined"}
+	for id, state := range want {
+		changes := got[id]
+		if len(changes) != 2 || changes[0] != ">pending" || changes[1] != "pending>"+state {
+			t.Errorf("%s: got changes %v, want [>pending pending>%s]", id, changes, state)
+		}
+	}
+
+	err = w.Run(ctx)
+	if !errors.Is(err, revolut.ErrWatcherStarted) {
+		t.Errorf("second Run: got %v, want ErrWatcherStarted", err)
+	}
+}
+
+func TestWatcherCancel(t *testing.T) {
+	srv, c, ids := scheduled(t, 1)
+	defer srv.Close()
+
+	w := c.NewWatcher(fastPolls)
+	w.Add(ids...)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+
+	<-w.Changes()
+	cancel()
+	for range w.Changes() {
+	}
+
+	err := <-done
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context canceled", err)
+	}
+}
@@ -1,24 +1,107 @@
 package revolut
 
-// WebhookRequest only holds the URL to add.
+import "context"
+
+// WebhookRequest holds the URL to add or change, and optionally the events to send to it.
 type WebhookRequest struct {
 	// URL must be secure.
+	URL string `json:"url,omitempty"`
+	// Events to subscribe to. All events are sent if this is empty.
+	Events []string `json:"events,omitempty"`
+}
+
+// Webhook is a registered endpoint for events.
+type Webhook struct {
+	// ID of the webhook.
+	ID string `json:"id"`
+	// URL events are posted to.
 	URL string `json:"url"`
+	// Events is the list of subscribed event types, such as EventCreated and EventStateChange.
+	Events []string `json:"events,omitempty"`
+	// SigningSecret verifies the signatures of delivered events. It is sent when the webhook is created.
+	SigningSecret string `json:"signing_secret,omitempty"`
 }
 
 // AddWebhook adds URLs to post events to when transactions are created or updated.
-func (c *Client) AddWebhook(url string) error {
-	hook := WebhookRequest{
+// The new webhook is returned with the ID needed to update or delete it.
+func (c *Client) AddWebhook(url string) (*Webhook, error) {
+	return c.AddWebhookContext(context.Background(), url)
+}
+
+// AddWebhookContext is AddWebhook with a context for deadlines and cancellation.
+func (c *Client) AddWebhookContext(ctx context.Context, url string) (*Webhook, error) {
+	req := WebhookRequest{
 		URL: url,
 	}
-	contents, code, err := c.PostJSON(epWebhook, hook)
+
+	var hook Webhook
+	err := c.do(ctx, "POST", c.v2(epWebhooks), req, &hook)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if code != 204 {
-		return jsonError(contents)
+	return &hook, nil
+}
+
+// ListWebhooks returns all registered webhooks.
+func (c *Client) ListWebhooks() ([]Webhook, error) {
+	return c.ListWebhooksContext(context.Background())
+}
+
+// ListWebhooksContext is ListWebhooks with a context for deadlines and cancellation.
+func (c *Client) ListWebhooksContext(ctx context.Context) ([]Webhook, error) {
+	var list []Webhook
+	err := c.do(ctx, "GET", c.v2(epWebhooks), nil, &list)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return list, nil
+}
+
+// GetWebhook gets a webhook by ID.
+func (c *Client) GetWebhook(id string) (*Webhook, error) {
+	return c.GetWebhookContext(context.Background(), id)
+}
+
+// GetWebhookContext is GetWebhook with a context for deadlines and cancellation.
+func (c *Client) GetWebhookContext(ctx context.Context, id string) (*Webhook, error) {
+	var hook Webhook
+	err := c.do(ctx, "GET", c.v2(epWebhooks+"/"+id), nil, &hook)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hook, nil
+}
+
+// UpdateWebhook changes the URL and/or subscribed events of a webhook. Empty values are left as they are.
+func (c *Client) UpdateWebhook(id, url string, events []string) (*Webhook, error) {
+	return c.UpdateWebhookContext(context.Background(), id, url, events)
+}
+
+// UpdateWebhookContext is UpdateWebhook with a context for deadlines and cancellation.
+func (c *Client) UpdateWebhookContext(ctx context.Context, id, url string, events []string) (*Webhook, error) {
+	req := WebhookRequest{
+		URL:    url,
+		Events: events,
+	}
+
+	var hook Webhook
+	err := c.do(ctx, "PATCH", c.v2(epWebhooks+"/"+id), req, &hook)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hook, nil
+}
+
+// DeleteWebhook removes a webhook by ID.
+func (c *Client) DeleteWebhook(id string) error {
+	return c.DeleteWebhookContext(context.Background(), id)
+}
+
+// DeleteWebhookContext is DeleteWebhook with a context for deadlines and cancellation.
+func (c *Client) DeleteWebhookContext(ctx context.Context, id string) error {
+	return c.do(ctx, "DELETE", c.v2(epWebhooks+"/"+id), nil, nil)
 }
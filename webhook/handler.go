@@ -0,0 +1,208 @@
+// Package webhook receives the events Revolut posts to registered webhook URLs.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Urethramancer/revolut"
+)
+
+// DefaultMaxBodySize is the largest event the Handler accepts unless it sets its own limit.
+const DefaultMaxBodySize = 1 << 20
+
+// Envelope is the part common to every event, used to decide how to decode the rest.
+type Envelope struct {
+	// Event is the event name, such as revolut.EventCreated.
+	Event string `json:"event"`
+	// Timestamp of the event.
+	Timestamp revolut.Time `json:"timestamp"`
+	// Data is the undecoded payload.
+	Data json.RawMessage `json:"data"`
+}
+
+// Handler is an http.Handler for webhook posts. It decodes each event and calls the
+// callback for its type. A callback returning an error makes the handler respond with
+// status 500, so Revolut will deliver the event again later.
+type Handler struct {
+	// OnTransactionCreated is called for revolut.EventCreated.
+	OnTransactionCreated func(ctx context.Context, e revolut.TransactionCreatedEvent) error
+	// OnTransactionStateChanged is called for revolut.EventStateChange.
+	OnTransactionStateChanged func(ctx context.Context, e revolut.TransactionChangedEvent) error
+	// OnUnknown is called for other events, and for known ones without a callback.
+	// They are acknowledged and dropped if it's nil.
+	OnUnknown func(ctx context.Context, env Envelope, body []byte) error
+	// MaxBodySize limits the request body. DefaultMaxBodySize is used if it's 0.
+	MaxBodySize int64
+	// Verifier checks the signature of each request before it's decoded. Unsigned and
+	// forged events are rejected with status 401. Set it in production.
+	Verifier *Verifier
+	// Dedupe skips events which were already handled. Revolut may deliver an event
+	// more than once, so set it unless the callbacks are idempotent. An event is only
+	// marked as handled after its callback succeeds.
+	Dedupe Store
+	// Log records every verified event before it's dispatched. If recording fails,
+	// the handler responds with status 500 so the event is delivered again.
+	Log DeliveryLog
+	// Now returns the current time for the Log. Leave it nil to use time.Now.
+	Now func() time.Time
+
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+var (
+	// ErrBadEvent is returned by Dispatch for bodies which aren't valid events.
+	// ServeHTTP answers them with status 400.
+	ErrBadEvent = errors.New("malformed event")
+	// ErrEventRunning is returned by Dispatch for an event which is already being handled
+	// by another call. ServeHTTP answers it with status 500, so it's delivered again later.
+	ErrEventRunning = errors.New("event is already being handled")
+)
+
+// ServeHTTP reads, decodes and dispatches one event.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	max := h.MaxBodySize
+	if max <= 0 {
+		max = DefaultMaxBodySize
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, max))
+	if err != nil {
+		if int64(len(body)) >= max {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		} else {
+			http.Error(w, "couldn't read request body", http.StatusBadRequest)
+		}
+		return
+	}
+
+	if h.Verifier != nil {
+		err = h.Verifier.Verify(r.Header, body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	if h.Log != nil && json.Valid(body) {
+		now := time.Now()
+		if h.Now != nil {
+			now = h.Now()
+		}
+
+		err = h.Log.Record(Delivery{Received: now, Body: body})
+		if err != nil {
+			http.Error(w, "event not recorded", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	err = h.Dispatch(r.Context(), body)
+	if errors.Is(err, ErrBadEvent) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, "event not processed", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Dispatch decodes a raw event body and calls the matching callback.
+// It's what ServeHTTP uses, and can be called directly to replay stored events.
+// Events already marked in the Dedupe store are skipped without error.
+func (h *Handler) Dispatch(ctx context.Context, body []byte) error {
+	var env Envelope
+	err := json.Unmarshal(body, &env)
+	if err != nil || env.Event == "" {
+		return ErrBadEvent
+	}
+
+	if h.Dedupe == nil {
+		return h.dispatch(ctx, env, body)
+	}
+
+	key := EventKey(env, body)
+	if !h.start(key) {
+		return ErrEventRunning
+	}
+
+	defer h.finish(key)
+	seen, err := h.Dedupe.Seen(key)
+	if err != nil {
+		return err
+	}
+
+	if seen {
+		return nil
+	}
+
+	err = h.dispatch(ctx, env, body)
+	if err != nil {
+		return err
+	}
+
+	return h.Dedupe.Mark(key)
+}
+
+// start claims an event key for one Dispatch call, returning false if another call has it.
+func (h *Handler) start(key string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.running[key] {
+		return false
+	}
+
+	if h.running == nil {
+		h.running = make(map[string]bool)
+	}
+	h.running[key] = true
+	return true
+}
+
+// finish releases an event key claimed by start.
+func (h *Handler) finish(key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.running, key)
+}
+
+// dispatch calls the callback for a decoded envelope.
+func (h *Handler) dispatch(ctx context.Context, env Envelope, body []byte) error {
+	var err error
+	switch {
+	case env.Event == revolut.EventCreated && h.OnTransactionCreated != nil:
+		var e revolut.TransactionCreatedEvent
+		err = json.Unmarshal(body, &e)
+		if err != nil {
+			return ErrBadEvent
+		}
+		return h.OnTransactionCreated(ctx, e)
+	case env.Event == revolut.EventStateChange && h.OnTransactionStateChanged != nil:
+		var e revolut.TransactionChangedEvent
+		err = json.Unmarshal(body, &e)
+		if err != nil {
+			return ErrBadEvent
+		}
+		return h.OnTransactionStateChanged(ctx, e)
+	case h.OnUnknown != nil:
+		return h.OnUnknown(ctx, env, body)
+	}
+
+	return nil
+}
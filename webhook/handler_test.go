@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Urethramancer/revolut"
+)
+
+const createdEvent = `{"event":"TransactionCreated","timestamp":"2020-01-01T00:00:00Z","data":{"id":"2","type":"transfer","state":"pending","reference":"rent"}}`
+
+func TestHandlerStatus(t *testing.T) {
+	failing := func(ctx context.Context, e revolut.TransactionChangedEvent) error { return errors.New("database down") }
+	tests := []struct {
+		name    string
+		method  string
+		body    string
+		handler *Handler
+		want    int
+	}{
+		{"wrong method", "GET", "", &Handler{}, http.StatusMethodNotAllowed},
+		{"too large", "POST", testEvent, &Handler{MaxBodySize: 16}, http.StatusRequestEntityTooLarge},
+		{"not JSON", "POST", "event", &Handler{}, http.StatusBadRequest},
+		{"no event name", "POST", `{"data":{}}`, &Handler{}, http.StatusBadRequest},
+		{"malformed payload", "POST", `{"event":"TransactionStateChanged","data":[]}`, &Handler{OnTransactionStateChanged: failing}, http.StatusBadRequest},
+		{"unsigned", "POST", testEvent, &Handler{Verifier: &Verifier{Secrets: []string{"secret"}}}, http.StatusUnauthorized},
+		{"callback failed", "POST", testEvent, &Handler{OnTransactionStateChanged: failing}, http.StatusInternalServerError},
+		{"no callback", "POST", testEvent, &Handler{}, http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, httptest.NewRequest(tt.method, "/hook", strings.NewReader(tt.body)))
+			if rec.Code != tt.want {
+				t.Errorf("status %d, want %d", rec.Code, tt.want)
+			}
+
+			if tt.want == http.StatusMethodNotAllowed && rec.Header().Get("Allow") != "POST" {
+				t.Errorf("Allow header %q, want POST", rec.Header().Get("Allow"))
+			}
+		})
+	}
+}
+
+func TestHandlerDispatch(t *testing.T) {
+	var got []string
+	h := &Handler{
+		OnTransactionCreated: func(ctx context.Context, e revolut.TransactionCreatedEvent) error {
+			got = append(got, "created "+e.Data.ID+" "+e.Data.Type.String()+" "+e.Data.Reference)
+			return nil
+		},
+		OnTransactionStateChanged: func(ctx context.Context, e revolut.TransactionChangedEvent) error {
+			got = append(got, "changed "+e.Data.ID+" "+e.Data.OldState.String()+" "+e.Data.NewState.String())
+			return nil
+		},
+		OnUnknown: func(ctx context.Context, env Envelope, body []byte) error {
+			got = append(got, "unknown "+env.Event+" "+string(env.Data))
+			return nil
+		},
+	}
+
+	bodies := []string{
+		createdEvent,
+		testEvent,
+		`{"event":"PayoutLinkCreated","timestamp":"2020-01-01T00:00:00Z","data":{"id":"3"}}`,
+	}
+	for _, body := range bodies {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("POST", "/hook", strings.NewReader(body)))
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("status %d, want %d", rec.Code, http.StatusNoContent)
+		}
+	}
+
+	want := []string{
+		"created 2 transfer rent",
+		"changed 1 pending completed",
+		`unknown PayoutLinkCreated {"id":"3"}`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got calls\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestHandlerUnknownFallback(t *testing.T) {
+	var events []string
+	h := &Handler{
+		OnTransactionCreated: func(ctx context.Context, e revolut.TransactionCreatedEvent) error {
+			t.Error("OnTransactionCreated called for a state change")
+			return nil
+		},
+		OnUnknown: func(ctx context.Context, env Envelope, body []byte) error {
+			events = append(events, env.Event)
+			return nil
+		},
+	}
+
+	// A known event without its own callback goes to OnUnknown.
+	err := h.Dispatch(context.Background(), []byte(testEvent))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 1 || events[0] != revolut.EventStateChange {
+		t.Errorf("OnUnknown got %v, want [%s]", events, revolut.EventStateChange)
+	}
+}
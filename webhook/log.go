@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Delivery is one received event, as recorded in a DeliveryLog.
+type Delivery struct {
+	// Received is when the event arrived.
+	Received time.Time `json:"received"`
+	// Body is the raw event.
+	Body json.RawMessage `json:"body"`
+}
+
+// DeliveryLog records every verified event before it's handled, so events can be
+// replayed into the handlers after an outage.
+type DeliveryLog interface {
+	Record(d Delivery) error
+}
+
+// FileLog is a DeliveryLog writing one JSON object per line.
+type FileLog struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// OpenFileLog opens or creates a log file for appending. Close it when done.
+func OpenFileLog(path string) (*FileLog, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileLog{f: f}, nil
+}
+
+// Record appends a delivery to the file and flushes it to disk.
+func (l *FileLog) Record(d Delivery) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.f.Write(append(data, '\n'))
+	if err != nil {
+		return err
+	}
+
+	return l.f.Sync()
+}
+
+// Close the file.
+func (l *FileLog) Close() error {
+	return l.f.Close()
+}
+
+// ReadLog loads the deliveries in a log file received at or after since.
+// Lines which don't decode, such as a partial last line left by a crash, are skipped.
+func ReadLog(path string, since time.Time) ([]Delivery, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+	var list []Delivery
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		var d Delivery
+		if json.Unmarshal(line, &d) == nil && !d.Received.Before(since) {
+			list = append(list, d)
+		}
+
+		if err == io.EOF {
+			return list, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Replay dispatches the deliveries in a log file received at or after since to h, in order.
+// With a dedupe Store on h, events which were already handled are skipped. Malformed events
+// are skipped, while the first callback error stops the replay and is returned.
+func Replay(ctx context.Context, path string, since time.Time, h *Handler) error {
+	list, err := ReadLog(path, since)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range list {
+		err = ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		err = h.Dispatch(ctx, d.Body)
+		if err != nil && !errors.Is(err, ErrBadEvent) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadLog(t *testing.T) {
+	path, done := tempPath(t)
+	defer done()
+	l, err := OpenFileLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	// The last event is larger than the handler accepts by default, to show the log has no line limit.
+	large := `{"event":"Large","timestamp":"2020-01-01T00:00:00Z","data":{"note":"` + strings.Repeat("x", DefaultMaxBodySize*2) + `"}}`
+	bodies := []string{testEvent, createdEvent, large}
+	for i, body := range bodies {
+		err = l.Record(Delivery{Received: start.Add(time.Minute * time.Duration(i)), Body: json.RawMessage(body)})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	l.Close()
+
+	// A line that isn't a delivery, and a partial last line as left by a crash.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("not json\n{\"received\":\"2020-01-01T00:05:00Z\",\"bo")
+	f.Close()
+
+	tests := []struct {
+		name  string
+		since time.Time
+		want  int
+	}{
+		{"all", time.Time{}, 3},
+		{"since second", start.Add(time.Minute), 2},
+		{"after all", start.Add(time.Hour), 0},
+	}
+
+	for _, tt := range tests {
+		list, err := ReadLog(path, tt.since)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(list) != tt.want {
+			t.Errorf("%s: got %d deliveries, want %d", tt.name, len(list), tt.want)
+			continue
+		}
+
+		for i, d := range list {
+			if want := bodies[len(bodies)-tt.want+i]; string(d.Body) != want {
+				t.Errorf("%s: delivery %d has body %.60s, want %.60s", tt.name, i, d.Body, want)
+			}
+		}
+	}
+
+	var events []string
+	h := &Handler{
+		OnUnknown: func(ctx context.Context, env Envelope, body []byte) error {
+			events = append(events, env.Event)
+			return nil
+		},
+	}
+	err = Replay(context.Background(), path, time.Time{}, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(events, " ") != "TransactionStateChanged TransactionCreated Large" {
+		t.Errorf("replayed %v", events)
+	}
+}
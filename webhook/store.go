@@ -0,0 +1,131 @@
+package webhook
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Urethramancer/revolut"
+)
+
+// Store remembers which events have been handled, so redeliveries can be skipped.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Seen reports whether key was marked as handled.
+	Seen(key string) (bool, error)
+	// Mark records key as handled. The Handler only calls it once the callback has succeeded,
+	// so an event whose callback fails, or is cut short by a crash, is handled again when redelivered.
+	Mark(key string) error
+}
+
+// EventKey identifies an event for deduplication. Transaction events are keyed by event name,
+// transaction ID and new state, so a redelivery has the same key while a later state change doesn't.
+// Other events are keyed by a hash of the body.
+func EventKey(env Envelope, body []byte) string {
+	var data struct {
+		ID       string `json:"id"`
+		State    string `json:"state"`
+		NewState string `json:"new_state"`
+	}
+
+	if json.Unmarshal(env.Data, &data) == nil && data.ID != "" {
+		switch env.Event {
+		case revolut.EventCreated:
+			return env.Event + ":" + data.ID
+		case revolut.EventStateChange:
+			return env.Event + ":" + data.ID + ":" + data.NewState
+		}
+	}
+
+	sum := sha256.Sum256(body)
+	return env.Event + ":" + hex.EncodeToString(sum[:])
+}
+
+// MemoryStore is a Store which lasts as long as the process.
+type MemoryStore struct {
+	mu   sync.Mutex
+	keys map[string]bool
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{keys: make(map[string]bool)}
+}
+
+// Seen reports whether key was marked.
+func (s *MemoryStore) Seen(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.keys[key], nil
+}
+
+// Mark records key.
+func (s *MemoryStore) Mark(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[key] = true
+	return nil
+}
+
+// FileStore is a Store kept in an append-only file, so it survives restarts.
+// Each line is a key prefixed with "+".
+type FileStore struct {
+	MemoryStore
+	f *os.File
+}
+
+// OpenFileStore loads or creates a FileStore. Close it when done.
+func OpenFileStore(path string) (*FileStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FileStore{MemoryStore: *NewMemoryStore(), f: f}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "+") {
+			s.keys[line[1:]] = true
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Mark records key, writing it to the file and flushing it to disk if it's new.
+func (s *FileStore) Mark(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keys[key] {
+		return nil
+	}
+
+	_, err := s.f.WriteString("+" + key + "\n")
+	if err != nil {
+		return err
+	}
+
+	err = s.f.Sync()
+	if err != nil {
+		return err
+	}
+
+	s.keys[key] = true
+	return nil
+}
+
+// Close the file.
+func (s *FileStore) Close() error {
+	return s.f.Close()
+}
@@ -0,0 +1,122 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testEvent = `{"event":"TransactionStateChanged","timestamp":"2020-01-01T00:00:00Z","data":{"id":"1","old_state":"pending","new_state":"completed"}}`
+
+// tempPath returns a path in a new temporary directory, and a function removing it.
+func tempPath(t *testing.T) (string, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "webhook")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return filepath.Join(dir, "seen"), func() { os.RemoveAll(dir) }
+}
+
+func TestFileStoreMarksAfterSuccess(t *testing.T) {
+	path, done := tempPath(t)
+	defer done()
+	calls := 0
+	fail := true
+	h := &Handler{
+		OnUnknown: func(ctx context.Context, env Envelope, body []byte) error {
+			calls++
+			if fail {
+				return errors.New("down")
+			}
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name  string
+		fail  bool
+		err   bool
+		calls int
+	}{
+		{"failing callback", true, true, 1},
+		{"redelivery after failure", false, false, 2},
+		{"redelivery after success", false, false, 2},
+	}
+
+	for _, tt := range tests {
+		// Reopen the store each time, as after a restart.
+		store, err := OpenFileStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		h.Dedupe = store
+		fail = tt.fail
+		err = h.Dispatch(context.Background(), []byte(testEvent))
+		store.Close()
+		if (err != nil) != tt.err {
+			t.Errorf("%s: error %v", tt.name, err)
+		}
+		if calls != tt.calls {
+			t.Errorf("%s: %d calls, want %d", tt.name, calls, tt.calls)
+		}
+	}
+}
+
+func TestFileStoreSeenDoesNotMark(t *testing.T) {
+	path, done := tempPath(t)
+	defer done()
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A crash between Seen and Mark must leave the event unhandled.
+	seen, err := store.Seen("a")
+	store.Close()
+	if seen || err != nil {
+		t.Fatalf("Seen = %v, %v", seen, err)
+	}
+
+	store, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer store.Close()
+	seen, _ = store.Seen("a")
+	if seen {
+		t.Error("key was recorded by Seen")
+	}
+}
+
+func TestDispatchRunning(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	h := &Handler{
+		Dedupe: NewMemoryStore(),
+		OnUnknown: func(ctx context.Context, env Envelope, body []byte) error {
+			close(started)
+			<-release
+			return nil
+		},
+	}
+
+	done := make(chan error)
+	go func() { done <- h.Dispatch(context.Background(), []byte(testEvent)) }()
+	<-started
+	err := h.Dispatch(context.Background(), []byte(testEvent))
+	if !errors.Is(err, ErrEventRunning) {
+		t.Errorf("got %v, want ErrEventRunning", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
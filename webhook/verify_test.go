@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	body := []byte(testEvent)
+	sig, ts := Sign("new", now, body)
+	oldSig, _ := Sign("old", now, body)
+	staleSig, staleTS := Sign("new", now.Add(-DefaultTolerance-time.Second), body)
+	futureSig, futureTS := Sign("new", now.Add(DefaultTolerance+time.Second), body)
+	nearSig, nearTS := Sign("new", now.Add(DefaultTolerance-time.Second), body)
+
+	tests := []struct {
+		name      string
+		signature string
+		timestamp string
+		body      []byte
+		secrets   []string
+		want      error
+	}{
+		{"valid", sig, ts, body, []string{"new"}, nil},
+		{"unsigned", "", "", body, []string{"new"}, ErrNoSignature},
+		{"no timestamp", sig, "", body, []string{"new"}, ErrNoSignature},
+		{"bad timestamp", sig, "yesterday", body, []string{"new"}, ErrBadTimestamp},
+		{"stale", staleSig, staleTS, body, []string{"new"}, ErrStaleTimestamp},
+		{"future", futureSig, futureTS, body, []string{"new"}, ErrStaleTimestamp},
+		{"within tolerance", nearSig, nearTS, body, []string{"new"}, nil},
+		{"wrong secret", sig, ts, body, []string{"other"}, ErrSignatureMismatch},
+		{"changed body", sig, ts, []byte(createdEvent), []string{"new"}, ErrSignatureMismatch},
+		{"old secret during rotation", oldSig, ts, body, []string{"old", "new"}, nil},
+		{"new secret during rotation", sig, ts, body, []string{"old", "new"}, nil},
+		{"several signatures", oldSig + ", " + sig, ts, body, []string{"new"}, nil},
+		{"malformed entry first", "v1=zz, " + sig, ts, body, []string{"new"}, nil},
+		{"malformed only", "v1=not-hex", ts, body, []string{"new"}, ErrSignatureMismatch},
+		{"other version", "v0=" + sig[3:], ts, body, []string{"new"}, ErrSignatureMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			if tt.signature != "" {
+				h.Set(SignatureHeader, tt.signature)
+			}
+			if tt.timestamp != "" {
+				h.Set(TimestampHeader, tt.timestamp)
+			}
+
+			v := &Verifier{Secrets: tt.secrets, Now: func() time.Time { return now }}
+			err := v.Verify(h, tt.body)
+			if err != tt.want {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyTolerance(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	body := []byte(testEvent)
+	sig, ts := Sign("secret", now.Add(-time.Second*30), body)
+	h := http.Header{}
+	h.Set(SignatureHeader, sig)
+	h.Set(TimestampHeader, ts)
+
+	v := &Verifier{Secrets: []string{"secret"}, Tolerance: time.Second * 10, Now: func() time.Time { return now }}
+	if err := v.Verify(h, body); err != ErrStaleTimestamp {
+		t.Errorf("30s old with 10s tolerance: got %v, want %v", err, ErrStaleTimestamp)
+	}
+
+	v.Tolerance = time.Minute
+	if err := v.Verify(h, body); err != nil {
+		t.Errorf("30s old with 1m tolerance: got %v", err)
+	}
+}
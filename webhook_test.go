@@ -0,0 +1,45 @@
+package revolut
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookPaths(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Method + " " + r.URL.Path
+		w.Write([]byte(`{"id":"1","url":"https://example.com","signing_secret":"wsk_abc"}`))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL+"/api/1.0/")
+	ctx := context.Background()
+	tests := []struct {
+		want string
+		call func() error
+	}{
+		{"POST /api/2.0/webhooks", func() error { _, err := c.AddWebhookContext(ctx, "https://example.com"); return err }},
+		{"GET /api/2.0/webhooks/1", func() error { _, err := c.GetWebhookContext(ctx, "1"); return err }},
+		{"PATCH /api/2.0/webhooks/1", func() error { _, err := c.UpdateWebhookContext(ctx, "1", "", nil); return err }},
+		{"DELETE /api/2.0/webhooks/1", func() error { return c.DeleteWebhookContext(ctx, "1") }},
+		{"GET /api/1.0/accounts/1", func() error { _, err := c.GetAccountContext(ctx, "1"); return err }},
+	}
+
+	hook, err := c.AddWebhookContext(ctx, "https://example.com")
+	if err != nil || hook.ID != "1" || hook.SigningSecret != "wsk_abc" {
+		t.Errorf("AddWebhook: got %+v, %v, want ID 1 and secret wsk_abc", hook, err)
+	}
+
+	for _, tt := range tests {
+		got = ""
+		if err := tt.call(); err != nil {
+			t.Errorf("%s: %v", tt.want, err)
+		}
+		if got != tt.want {
+			t.Errorf("got %s, want %s", got, tt.want)
+		}
+	}
+}